@@ -0,0 +1,227 @@
+package chert
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAddress is returned for addresses that are malformed, fail their
+// checksum or belong to another network
+var ErrInvalidAddress = errors.New("invalid address")
+
+// AddressPayloadSize is the length in bytes of the payload of an address
+const AddressPayloadSize = 20
+
+// AddressKind distinguishes account addresses from stealth addresses
+type AddressKind string
+
+const (
+	AddressKindStandard AddressKind = "standard"
+	AddressKindStealth  AddressKind = "stealth"
+)
+
+// addressPrefixes maps each network and kind to its bech32m human-readable part
+var addressPrefixes = map[Network]map[AddressKind]string{
+	NetworkMainnet: {AddressKindStandard: "chert", AddressKindStealth: "stealth"},
+	NetworkTestnet: {AddressKindStandard: "tchert", AddressKindStealth: "tstealth"},
+	NetworkDevnet:  {AddressKindStandard: "dchert", AddressKindStealth: "dstealth"},
+}
+
+// Address is a decoded address. Addresses are encoded as bech32m with a
+// prefix per network and kind, e.g. chert1... on mainnet and tchert1... on
+// testnet.
+type Address struct {
+	Kind    AddressKind
+	Network Network
+	Payload []byte
+}
+
+// EncodeAddress encodes a 20-byte payload as an address of kind on network
+func EncodeAddress(kind AddressKind, network Network, payload []byte) (string, error) {
+	hrp, ok := addressPrefixes[network][kind]
+	if !ok {
+		return "", fmt.Errorf("%w: no %s address prefix for network %q", ErrInvalidAddress, kind, network)
+	}
+
+	if len(payload) != AddressPayloadSize {
+		return "", fmt.Errorf("%w: payload must be %d bytes, got %d", ErrInvalidAddress, AddressPayloadSize, len(payload))
+	}
+
+	return bech32mEncode(hrp, convertBits(payload, 8, 5, true))
+}
+
+// ParseAddress decodes an address and verifies its checksum
+func ParseAddress(address string) (*Address, error) {
+	hrp, data, err := bech32mDecode(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+
+	for network, kinds := range addressPrefixes {
+		for kind, prefix := range kinds {
+			if prefix != hrp {
+				continue
+			}
+
+			payload := convertBits(data, 5, 8, false)
+			if payload == nil || len(payload) != AddressPayloadSize {
+				return nil, fmt.Errorf("%w: bad payload length", ErrInvalidAddress)
+			}
+
+			return &Address{Kind: kind, Network: network, Payload: payload}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown prefix %q", ErrInvalidAddress, hrp)
+}
+
+// ValidateAddress checks that address is well formed and belongs to network
+func ValidateAddress(address string, network Network) error {
+	_, err := parseAddressOn(address, network, "")
+	return err
+}
+
+// String encodes the address
+func (a *Address) String() string {
+	encoded, err := EncodeAddress(a.Kind, a.Network, a.Payload)
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
+
+// parseAddressOn parses address and checks its network and, if kind is set,
+// its kind
+func parseAddressOn(address string, network Network, kind AddressKind) (*Address, error) {
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Network != network {
+		return nil, fmt.Errorf("%w: %s is a %s address, expected %s", ErrInvalidAddress, address, parsed.Network, network)
+	}
+
+	if kind != "" && parsed.Kind != kind {
+		return nil, fmt.Errorf("%w: %s is a %s address, expected %s", ErrInvalidAddress, address, parsed.Kind, kind)
+	}
+
+	return parsed, nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32mConst is the checksum constant from BIP-350
+const bech32mConst = 0x2bc830a3
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32mEncode(hrp string, data []byte) (string, error) {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ bech32mConst
+
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, d := range data {
+		b.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		b.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+
+	return b.String(), nil
+}
+
+// bech32mDecode returns the human-readable part and the data without its checksum
+func bech32mDecode(s string) (string, []byte, error) {
+	if len(s) > 90 {
+		return "", nil, fmt.Errorf("address too long")
+	}
+
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("mixed case")
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, fmt.Errorf("missing separator or checksum")
+	}
+
+	hrp := lower[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("invalid character in prefix")
+		}
+	}
+
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		index := strings.IndexByte(bech32Charset, lower[i])
+		if index < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", lower[i])
+		}
+		data = append(data, byte(index))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != bech32mConst {
+		return "", nil, fmt.Errorf("checksum mismatch")
+	}
+
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups data from fromBits-bit to toBits-bit groups. It
+// returns nil if the input has non-zero padding when pad is false.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) []byte {
+	var acc, bits uint
+	maxv := uint(1)<<toBits - 1
+
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, value := range data {
+		acc = acc<<fromBits | uint(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,114 @@
+package chert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMainnetAddress = "chert1h48q9apc20z9egy2nj3vhcueg3vxrayjyr7r85"
+	testTestnetAddress = "tchert1h48q9apc20z9egy2nj3vhcueg3vxrayjtx8ekh"
+)
+
+func TestBech32mDecode(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantHRP string
+		wantErr bool
+	}{
+		// Valid strings from BIP-350
+		{input: "A1LQFN3A", wantHRP: "a"},
+		{input: "a1lqfn3a", wantHRP: "a"},
+		{input: "abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", wantHRP: "abcdef"},
+		{input: "split1checkupstagehandshakeupstreamerranterredcaperredlc445v", wantHRP: "split"},
+		{input: "?1v759aa", wantHRP: "?"},
+
+		// A valid bech32 string is not valid bech32m
+		{input: "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", wantErr: true},
+		{input: "M1VUXWEZ", wantErr: true},
+		{input: "a1Lqfn3a", wantErr: true},
+		{input: "1lqfn3a", wantErr: true},
+		{input: "a1lqfn3", wantErr: true},
+		{input: "a1lqfn3b", wantErr: true},
+		{input: "a1lqfn3i", wantErr: true},
+		{input: "a\x7f1lqfn3a", wantErr: true},
+		{input: "a1" + strings.Repeat("q", 89), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			hrp, _, err := bech32mDecode(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHRP, hrp)
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	payload := make([]byte, AddressPayloadSize)
+	stealth, err := EncodeAddress(AddressKindStealth, NetworkDevnet, payload)
+	require.NoError(t, err)
+
+	// Well-formed bech32m with a foreign prefix or a short payload
+	foreign, err := bech32mEncode("btc", convertBits(payload, 8, 5, true))
+	require.NoError(t, err)
+	short, err := bech32mEncode("chert", convertBits(payload[1:], 8, 5, true))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		address     string
+		wantNetwork Network
+		wantKind    AddressKind
+		wantErr     bool
+	}{
+		{name: "mainnet", address: testMainnetAddress, wantNetwork: NetworkMainnet, wantKind: AddressKindStandard},
+		{name: "testnet", address: testTestnetAddress, wantNetwork: NetworkTestnet, wantKind: AddressKindStandard},
+		{name: "upper case", address: strings.ToUpper(testMainnetAddress), wantNetwork: NetworkMainnet, wantKind: AddressKindStandard},
+		{name: "stealth", address: stealth, wantNetwork: NetworkDevnet, wantKind: AddressKindStealth},
+		{name: "mixed case", address: "Chert" + testMainnetAddress[5:], wantErr: true},
+		{name: "bad checksum", address: testMainnetAddress[:len(testMainnetAddress)-1] + "q", wantErr: true},
+		{name: "changed character", address: strings.Replace(testMainnetAddress, "h48", "h49", 1), wantErr: true},
+		{name: "prefix changed to testnet", address: "t" + testMainnetAddress, wantErr: true},
+		{name: "unknown prefix", address: foreign, wantErr: true},
+		{name: "short payload", address: short, wantErr: true},
+		{name: "empty", address: "", wantErr: true},
+		{name: "legacy hex", address: "0x0123456789abcdef0123456789abcdef01234567", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseAddress(tt.address)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAddress)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNetwork, parsed.Network)
+			assert.Equal(t, tt.wantKind, parsed.Kind)
+			assert.Equal(t, strings.ToLower(tt.address), parsed.String())
+		})
+	}
+}
+
+func TestValidateAddressNetwork(t *testing.T) {
+	assert.NoError(t, ValidateAddress(testMainnetAddress, NetworkMainnet))
+	assert.NoError(t, ValidateAddress(testTestnetAddress, NetworkTestnet))
+	assert.ErrorIs(t, ValidateAddress(testTestnetAddress, NetworkMainnet), ErrInvalidAddress)
+	assert.ErrorIs(t, ValidateAddress(testMainnetAddress, NetworkDevnet), ErrInvalidAddress)
+}
+
+func TestEncodeAddressErrors(t *testing.T) {
+	_, err := EncodeAddress(AddressKindStandard, Network("regtest"), make([]byte, AddressPayloadSize))
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+
+	_, err = EncodeAddress(AddressKindStandard, NetworkMainnet, make([]byte, AddressPayloadSize+1))
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
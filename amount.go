@@ -0,0 +1,228 @@
+package chert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidAmount is returned for amounts that cannot be parsed or that
+// have more decimal places than their denomination allows
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// AmountDecimals is the number of decimal places of one CHERT. Amounts are
+// held as whole base units of 10^-AmountDecimals CHERT.
+const AmountDecimals = 9
+
+// Denomination is a unit amounts can be written in, given as the number of
+// decimal places it is above the base unit
+type Denomination uint
+
+const (
+	DenomNanoChert  Denomination = 0
+	DenomMicroChert Denomination = 3
+	DenomMilliChert Denomination = 6
+	DenomChert      Denomination = AmountDecimals
+)
+
+// Amount is an exact token amount. The zero value is zero. Amounts marshal
+// to JSON as decimal CHERT strings such as "100.5" and unmarshal from either
+// strings or JSON numbers.
+type Amount struct {
+	units *big.Int
+}
+
+// NewAmount returns an amount of baseUnits base units
+func NewAmount(baseUnits int64) Amount {
+	return Amount{units: big.NewInt(baseUnits)}
+}
+
+// NewAmountFromBig returns an amount of baseUnits base units
+func NewAmountFromBig(baseUnits *big.Int) Amount {
+	if baseUnits == nil {
+		return Amount{}
+	}
+	return Amount{units: new(big.Int).Set(baseUnits)}
+}
+
+// ParseAmount parses a decimal CHERT amount such as "100" or "0.000000001"
+func ParseAmount(s string) (Amount, error) {
+	return ParseAmountIn(s, DenomChert)
+}
+
+// ParseAmountIn parses a decimal amount written in denom
+func ParseAmountIn(s string, denom Denomination) (Amount, error) {
+	str := strings.TrimSpace(s)
+
+	negative := false
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		negative = str[0] == '-'
+		str = str[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(str, ".")
+	if whole == "" && fraction == "" {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	if !isDigits(whole) || !isDigits(fraction) {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(denom) {
+		return Amount{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, s, denom)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(denom)-len(fraction))
+	units, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	if negative {
+		units.Neg(units)
+	}
+
+	return Amount{units: units}, nil
+}
+
+// MustParseAmount is like ParseAmount but panics on error. It is intended
+// for constants in code.
+func MustParseAmount(s string) Amount {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// BaseUnits returns the amount in base units
+func (a Amount) BaseUnits() *big.Int {
+	if a.units == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.units)
+}
+
+// In formats the amount in denom without trailing zeros
+func (a Amount) In(denom Denomination) string {
+	units := a.BaseUnits()
+
+	sign := ""
+	if units.Sign() < 0 {
+		sign = "-"
+		units.Neg(units)
+	}
+
+	digits := units.String()
+	if denom == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= int(denom) {
+		digits = strings.Repeat("0", int(denom)-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-int(denom)]
+	fraction := strings.TrimRight(digits[len(digits)-int(denom):], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + fraction
+}
+
+// String formats the amount in CHERT
+func (a Amount) String() string {
+	return a.In(DenomChert)
+}
+
+// Add returns a + b
+func (a Amount) Add(b Amount) Amount {
+	return Amount{units: new(big.Int).Add(a.BaseUnits(), b.BaseUnits())}
+}
+
+// Sub returns a - b
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{units: new(big.Int).Sub(a.BaseUnits(), b.BaseUnits())}
+}
+
+// Mul returns a * n
+func (a Amount) Mul(n int64) Amount {
+	return Amount{units: new(big.Int).Mul(a.BaseUnits(), big.NewInt(n))}
+}
+
+// Quo returns a / n, truncated toward zero. It panics if n is zero.
+func (a Amount) Quo(n int64) Amount {
+	return Amount{units: new(big.Int).Quo(a.BaseUnits(), big.NewInt(n))}
+}
+
+// Cmp compares a and b and returns -1, 0 or +1
+func (a Amount) Cmp(b Amount) int {
+	return a.BaseUnits().Cmp(b.BaseUnits())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of a
+func (a Amount) Sign() int {
+	if a.units == nil {
+		return 0
+	}
+	return a.units.Sign()
+}
+
+// IsZero reports whether a is zero
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// MarshalText encodes the amount as a decimal CHERT string
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes a decimal CHERT string. An empty string is zero.
+func (a *Amount) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = Amount{}
+		return nil
+	}
+
+	parsed, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
+
+// UnmarshalJSON decodes a decimal CHERT amount from a JSON string or number.
+// null and "" decode as zero.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*a = Amount{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+		}
+		return a.UnmarshalText([]byte(s))
+	}
+
+	return a.UnmarshalText(data)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
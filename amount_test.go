@@ -0,0 +1,174 @@
+package chert
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantUnits string
+		wantErr   bool
+	}{
+		{input: "0", wantUnits: "0"},
+		{input: "100", wantUnits: "100000000000"},
+		{input: "12.5", wantUnits: "12500000000"},
+		{input: "0.000000001", wantUnits: "1"},
+		{input: "1.000000000000", wantUnits: "1000000000"},
+		{input: ".5", wantUnits: "500000000"},
+		{input: "5.", wantUnits: "5000000000"},
+		{input: " 7 ", wantUnits: "7000000000"},
+		{input: "+3", wantUnits: "3000000000"},
+		{input: "-0.25", wantUnits: "-250000000"},
+		{input: "-0", wantUnits: "0"},
+		{input: "123456789012345678901234567890", wantUnits: "123456789012345678901234567890000000000"},
+
+		{input: "0.0000000001", wantErr: true},
+		{input: "1.1234567891", wantErr: true},
+		{input: "", wantErr: true},
+		{input: " ", wantErr: true},
+		{input: ".", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: "--1", wantErr: true},
+		{input: "+-1", wantErr: true},
+		{input: "1-", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "1e9", wantErr: true},
+		{input: "0x10", wantErr: true},
+		{input: "1,000", wantErr: true},
+		{input: "1 000", wantErr: true},
+		{input: "NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := ParseAmount(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUnits, amount.BaseUnits().String())
+		})
+	}
+}
+
+func TestParseAmountIn(t *testing.T) {
+	tests := []struct {
+		input     string
+		denom     Denomination
+		wantUnits string
+		wantErr   bool
+	}{
+		{input: "250", denom: DenomMicroChert, wantUnits: "250000"},
+		{input: "0.001", denom: DenomMicroChert, wantUnits: "1"},
+		{input: "0.0001", denom: DenomMicroChert, wantErr: true},
+		{input: "1.5", denom: DenomMilliChert, wantUnits: "1500000"},
+		{input: "42", denom: DenomNanoChert, wantUnits: "42"},
+		{input: "42.0", denom: DenomNanoChert, wantUnits: "42"},
+		{input: "0.5", denom: DenomNanoChert, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		amount, err := ParseAmountIn(tt.input, tt.denom)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, ErrInvalidAmount, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.wantUnits, amount.BaseUnits().String(), tt.input)
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	tests := []struct {
+		units string
+		denom Denomination
+		want  string
+	}{
+		{units: "0", denom: DenomChert, want: "0"},
+		{units: "1", denom: DenomChert, want: "0.000000001"},
+		{units: "12500250000", denom: DenomChert, want: "12.50025"},
+		{units: "-250000000", denom: DenomChert, want: "-0.25"},
+		{units: "100000000000", denom: DenomChert, want: "100"},
+		{units: "12500250000", denom: DenomNanoChert, want: "12500250000"},
+		{units: "12500250000", denom: DenomMilliChert, want: "12500.25"},
+	}
+
+	for _, tt := range tests {
+		units, ok := new(big.Int).SetString(tt.units, 10)
+		require.True(t, ok)
+		assert.Equal(t, tt.want, NewAmountFromBig(units).In(tt.denom))
+	}
+
+	assert.Equal(t, "0", Amount{}.String())
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a := MustParseAmount("10")
+	b := MustParseAmount("0.000000003")
+
+	assert.Equal(t, "10.000000003", a.Add(b).String())
+	assert.Equal(t, "9.999999997", a.Sub(b).String())
+	assert.Equal(t, "-9.999999997", b.Sub(a).String())
+	assert.Equal(t, "0.000000009", b.Mul(3).String())
+	assert.Equal(t, "0.000000001", b.Quo(2).String())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, 0, Amount{}.Cmp(NewAmount(0)))
+	assert.True(t, Amount{}.IsZero())
+
+	// Operations never modify their operands
+	a.Add(b)
+	assert.Equal(t, "10", a.String())
+
+	assert.Panics(t, func() { MustParseAmount("1.2.3") })
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Amount Amount `json:"amount"`
+	}
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: `{"amount": "100.5"}`, want: "100.5"},
+		{input: `{"amount": 100.5}`, want: "100.5"},
+		{input: `{"amount": null}`, want: "0"},
+		{input: `{"amount": ""}`, want: "0"},
+		{input: `{"amount": "0.0000000001"}`, wantErr: true},
+		{input: `{"amount": 1e3}`, wantErr: true},
+		{input: `{"amount": true}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		v.Amount = MustParseAmount("1")
+		err := json.Unmarshal([]byte(tt.input), &v)
+		if tt.wantErr {
+			assert.Error(t, err, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, v.Amount.String(), tt.input)
+	}
+
+	data, err := json.Marshal(map[string]Amount{"amount": MustParseAmount("0.000000001")})
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":"0.000000001"}`, string(data))
+
+	// Amounts beyond float64 precision survive a round trip
+	large := strings.Repeat("9", 30) + ".123456789"
+	data, err = json.Marshal(MustParseAmount(large))
+	require.NoError(t, err)
+	var decoded Amount
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, large, decoded.String())
+}
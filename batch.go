@@ -0,0 +1,303 @@
+package chert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxBatchSize is the number of calls the batch helpers put in one request
+const MaxBatchSize = 100
+
+// BatchElem is one call in a JSON-RPC batch. After BatchCall returns, Result
+// holds the decoded result and Error the per-call error, if any.
+type BatchElem struct {
+	Method string
+	Params interface{}
+	Result interface{}
+	Error  error
+}
+
+// BatchCall sends all elements in one JSON-RPC 2.0 batch request and
+// matches the responses back by ID. The returned error covers the request
+// as a whole and is an *RPCError for method BatchMethod, whose RequestID is
+// that of the first element; errors of individual calls are set on their
+// elements as *RPCError.
+//
+// The batch passes through the interceptors as method BatchMethod, and
+// failures of the whole request are retried like Call when every method in
+// the batch may be retried.
+func (c *RPCClient) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, _ = withCallState(ctx, nil)
+	return c.chain(true)(ctx, BatchMethod, batch, nil)
+}
+
+// batchAttempt sends a batch once to node, with fresh request IDs. A failure
+// of the whole request is set on every element and returned as an *RPCError.
+func (c *RPCClient) batchAttempt(ctx context.Context, node *endpoint, batch []BatchElem) error {
+	requests := make([]JSONRPCRequest, len(batch))
+	ids := make([]uint64, len(batch))
+	index := make(map[uint64]int, len(batch))
+	for i, elem := range batch {
+		ids[i] = c.newID()
+		requests[i] = JSONRPCRequest{
+			JSONRPC: "2.0",
+			Method:  elem.Method,
+			Params:  elem.Params,
+			ID:      ids[i],
+		}
+		index[ids[i]] = i
+		batch[i].Error = nil
+		c.onRequest(ctx, elem.Method, ids[i])
+	}
+
+	start := time.Now()
+	err := c.batchCall(ctx, node.url(), batch, requests, index)
+	duration := time.Since(start)
+	c.pool.report(node, duration, err)
+
+	for i := range batch {
+		if err != nil {
+			batch[i].Error = err
+		}
+		if batch[i].Error != nil {
+			batch[i].Error = &RPCError{Method: batch[i].Method, RequestID: ids[i], Err: batch[i].Error}
+		}
+		c.onResponse(ctx, batch[i].Method, ids[i], duration, batch[i].Error)
+	}
+
+	callStateFrom(ctx).setRequestID(ids[0])
+	if err != nil {
+		return &RPCError{Method: BatchMethod, RequestID: ids[0], Attempts: 1, Err: err}
+	}
+	return nil
+}
+
+// batchCall sends the requests of a batch and sets the results and errors
+// of its elements
+func (c *RPCClient) batchCall(ctx context.Context, url string, batch []BatchElem, requests []JSONRPCRequest, index map[uint64]int) error {
+	var body json.RawMessage
+	if err := c.post(ctx, url, "batch", requests, &body); err != nil {
+		return err
+	}
+
+	// Servers answer a batch they cannot parse with a single error object
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '{' {
+		var single rpcResponse
+		if err := json.Unmarshal(body, &single); err != nil {
+			return fmt.Errorf("failed to decode RPC response: %w", err)
+		}
+		if single.Error != nil {
+			return single.Error
+		}
+		return fmt.Errorf("RPC batch answered with a single response")
+	}
+
+	var responses []rpcResponse
+	if err := json.Unmarshal(body, &responses); err != nil {
+		return fmt.Errorf("failed to decode RPC response: %w", err)
+	}
+
+	answered := make([]bool, len(batch))
+	for _, response := range responses {
+		id, ok := response.requestID()
+		if !ok {
+			continue
+		}
+
+		i, ok := index[id]
+		if !ok || answered[i] {
+			continue
+		}
+		answered[i] = true
+
+		elem := &batch[i]
+		if response.Error != nil {
+			elem.Error = response.Error
+		} else if err := response.decodeResult(elem.Result); err != nil {
+			elem.Error = fmt.Errorf("failed to decode RPC result: %w", err)
+		}
+	}
+
+	for i, ok := range answered {
+		if !ok {
+			batch[i].Error = fmt.Errorf("%w: no response in RPC batch", ErrResponseIDMismatch)
+		}
+	}
+
+	return nil
+}
+
+// BatchError reports the calls of a batch helper that failed. Errors is
+// aligned with the helper's input and is nil for calls that succeeded.
+type BatchError struct {
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	failed := 0
+	var first error
+	for _, err := range e.Errors {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d batched calls failed, first: %v", failed, len(e.Errors), first)
+}
+
+// Unwrap returns the individual errors, so errors.Is matches any of them
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// batchGet calls method once per params entry in batches of MaxBatchSize
+// and returns the results aligned with params. If any call fails, the
+// failed results are nil and a *BatchError is returned with the rest. A
+// batch request that fails as a whole fails each of its calls, and later
+// batches are still sent.
+//
+// A batch goes to a single node, so with quorum reads configured for method
+// each entry is instead sent as its own quorum call.
+func batchGet[T any](ctx context.Context, c *ChertClient, method string, params []interface{}) ([]*T, error) {
+	results := make([]*T, len(params))
+	errs := make([]error, len(params))
+	failed := false
+
+	if c.config.Quorum != nil && quorumMethods[method] {
+		for i, param := range params {
+			results[i] = new(T)
+			if err := c.readCall(ctx, method, []interface{}{param}, results[i]); err != nil {
+				results[i] = nil
+				errs[i] = err
+				failed = true
+			}
+		}
+
+		if failed {
+			return results, &BatchError{Errors: errs}
+		}
+		return results, nil
+	}
+
+	rpc := c.rpcClient
+	for start := 0; start < len(params); start += MaxBatchSize {
+		end := start + MaxBatchSize
+		if end > len(params) {
+			end = len(params)
+		}
+
+		batch := make([]BatchElem, end-start)
+		for i := range batch {
+			results[start+i] = new(T)
+			batch[i] = BatchElem{
+				Method: method,
+				Params: []interface{}{params[start+i]},
+				Result: results[start+i],
+			}
+		}
+
+		err := rpc.BatchCall(ctx, batch)
+		for i, elem := range batch {
+			if elem.Error == nil && err != nil {
+				// Failed before the elements were sent, e.g. by an interceptor
+				elem.Error = err
+			}
+			if elem.Error != nil {
+				results[start+i] = nil
+				errs[start+i] = elem.Error
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return results, &BatchError{Errors: errs}
+	}
+	return results, nil
+}
+
+// GetBalances retrieves the balances of many accounts, batching requests.
+// Results are aligned with addresses; see BatchError for partial failures.
+// With quorum reads configured, each balance is a separate quorum call.
+func (wm *WalletManager) GetBalances(ctx context.Context, addresses []string) ([]*Balance, error) {
+	params := make([]interface{}, len(addresses))
+	for i, address := range addresses {
+		params[i] = address
+	}
+
+	return batchGet[Balance](ctx, wm.client, "getBalance", params)
+}
+
+// GetBlocks retrieves many blocks by height, batching requests. Results are
+// aligned with heights; see BatchError for partial failures. With quorum
+// reads configured, each block is a separate quorum call.
+func (c *ChertClient) GetBlocks(ctx context.Context, heights []uint64) ([]*Block, error) {
+	params := make([]interface{}, len(heights))
+	for i, height := range heights {
+		params[i] = height
+	}
+
+	return batchGet[Block](ctx, c, "getBlock", params)
+}
+
+// GetTransactions retrieves many transactions by hash, batching requests.
+// Results are aligned with hashes; unknown hashes fail with
+// ErrTransactionNotFound inside the returned BatchError. With quorum reads
+// configured, each transaction is a separate quorum call.
+func (c *ChertClient) GetTransactions(ctx context.Context, hashes []string) ([]*Transaction, error) {
+	params := make([]interface{}, len(hashes))
+	for i, hash := range hashes {
+		params[i] = hash
+	}
+
+	results, err := batchGet[Transaction](ctx, c, "getTransaction", params)
+	if results == nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(hashes))
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		copy(errs, batchErr.Errors)
+	}
+
+	failed := false
+	for i, tx := range results {
+		switch {
+		case errs[i] != nil:
+			if errors.Is(errs[i], ErrNotFound) {
+				errs[i] = fmt.Errorf("%w: %s: %w", ErrTransactionNotFound, hashes[i], errs[i])
+			}
+		case tx.Hash == "":
+			results[i] = nil
+			errs[i] = fmt.Errorf("%w: %s", ErrTransactionNotFound, hashes[i])
+		case tx.NetworkID == "":
+			tx.NetworkID = c.networkID()
+		}
+		if errs[i] != nil {
+			failed = true
+		}
+	}
+
+	if failed {
+		return results, &BatchError{Errors: errs}
+	}
+	return results, nil
+}
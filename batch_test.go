@@ -0,0 +1,286 @@
+package chert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBalanceHandler answers getBalance with balances, counting batches
+func testBalanceHandler(t *testing.T, balances map[string]string) func(w http.ResponseWriter, req *testRPCRequest) {
+	return func(w http.ResponseWriter, req *testRPCRequest) {
+		if req.Method != "getBalance" {
+			req.fail(w, -32601, "method not found")
+			return
+		}
+
+		var address string
+		req.param(t, &address)
+		balance, ok := balances[address]
+		if !ok {
+			req.fail(w, -32000, "account not found")
+			return
+		}
+		req.reply(w, map[string]string{"available": balance, "pending": "0", "total": balance})
+	}
+}
+
+func TestGetBalancesBatches(t *testing.T) {
+	var requests int32
+	handle := testBalanceHandler(t, map[string]string{"a": "1", "c": "3"})
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		atomic.AddInt32(&requests, 1)
+		handle(w, req)
+	})
+	client := newTestClient(t, server, nil)
+
+	balances, err := client.Wallet.GetBalances(context.Background(), []string{"a", "b", "c"})
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Errors, 3)
+	assert.NoError(t, batchErr.Errors[0])
+	assert.Error(t, batchErr.Errors[1])
+	assert.NoError(t, batchErr.Errors[2])
+
+	require.Len(t, balances, 3)
+	assert.Equal(t, "1", balances[0].Available.String())
+	assert.Nil(t, balances[1])
+	assert.Equal(t, "3", balances[2].Available.String())
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+}
+
+func TestGetBalancesQuorum(t *testing.T) {
+	honest := testBalanceHandler(t, map[string]string{"a": "1", "b": "2"})
+	lying := testBalanceHandler(t, map[string]string{"a": "1", "b": "2000"})
+
+	// Calls that share an HTTP request arrived in a batch
+	var (
+		mu      sync.Mutex
+		seen    = make(map[*http.Request]bool)
+		batched int
+	)
+	var servers []*httptest.Server
+	var endpoints []string
+	for _, handle := range []func(http.ResponseWriter, *testRPCRequest){honest, honest, lying} {
+		handle := handle
+		server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+			mu.Lock()
+			if seen[req.HTTP] {
+				batched++
+			}
+			seen[req.HTTP] = true
+			mu.Unlock()
+
+			handle(w, req)
+		})
+		servers = append(servers, server)
+		endpoints = append(endpoints, server.URL)
+	}
+
+	for _, required := range []int{2, 3} {
+		client := newTestClient(t, servers[0], &ClientConfig{
+			Endpoints: endpoints,
+			Quorum:    &QuorumOptions{Required: required},
+		})
+
+		balances, err := client.Wallet.GetBalances(context.Background(), []string{"a", "b"})
+		require.Len(t, balances, 2)
+		assert.Equal(t, "1", balances[0].Available.String())
+
+		if required == 2 {
+			require.NoError(t, err)
+			assert.Equal(t, "2", balances[1].Available.String())
+			continue
+		}
+
+		// Only two of the three nodes agree on b
+		var batchErr *BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.NoError(t, batchErr.Errors[0])
+		assert.True(t, errors.Is(batchErr.Errors[1], ErrQuorumNotReached))
+		assert.Nil(t, balances[1])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Zero(t, batched)
+}
+
+func TestBatchCallRequestErrors(t *testing.T) {
+	newBatch := func() []BatchElem {
+		return []BatchElem{
+			{Method: "getBalance", Params: []interface{}{"a"}},
+			{Method: "getBalance", Params: []interface{}{"b"}},
+		}
+	}
+
+	t.Run("HTTP error", func(t *testing.T) {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			http.Error(w, "upstream failed", http.StatusBadGateway)
+		}))
+		t.Cleanup(server.Close)
+		client := newTestClient(t, server, &ClientConfig{
+			Retry: &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		})
+
+		batch := newBatch()
+		err := client.RPC().BatchCall(context.Background(), batch)
+
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, BatchMethod, rpcErr.Method)
+		assert.NotZero(t, rpcErr.RequestID)
+		assert.Equal(t, 2, rpcErr.Attempts)
+		assert.EqualValues(t, 2, atomic.LoadInt32(&attempts))
+		assert.ErrorIs(t, err, ErrUnavailable)
+
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+
+		// Each element reports its own request
+		for _, elem := range batch {
+			var elemErr *RPCError
+			require.ErrorAs(t, elem.Error, &elemErr)
+			assert.Equal(t, "getBalance", elemErr.Method)
+			assert.ErrorIs(t, elem.Error, ErrUnavailable)
+		}
+		assert.Equal(t, rpcErr.RequestID, batch[0].Error.(*RPCError).RequestID)
+	})
+
+	t.Run("single error response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      nil,
+				"error":   map[string]interface{}{"code": -32700, "message": "parse error"},
+			})
+		}))
+		t.Cleanup(server.Close)
+		client := newTestClient(t, server, nil)
+
+		err := client.RPC().BatchCall(context.Background(), newBatch())
+
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, BatchMethod, rpcErr.Method)
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+	})
+
+	t.Run("circuit open", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "upstream failed", http.StatusBadGateway)
+		}))
+		t.Cleanup(server.Close)
+		client := newTestClient(t, server, &ClientConfig{
+			Retry:          &RetryPolicy{MaxAttempts: 1},
+			CircuitBreaker: &CircuitBreakerOptions{FailureThreshold: 1, CoolDown: time.Hour},
+		})
+
+		require.Error(t, client.RPC().BatchCall(context.Background(), newBatch()))
+		err := client.RPC().BatchCall(context.Background(), newBatch())
+
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, BatchMethod, rpcErr.Method)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	})
+}
+
+func TestGetBalancesChunkFailure(t *testing.T) {
+	addresses := make([]string, MaxBatchSize+50)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("addr%d", i)
+	}
+
+	tests := []struct {
+		name      string
+		failSize  int
+		intercept bool
+		failed    func(i int) bool
+	}{
+		{name: "later chunk", failSize: 50, failed: func(i int) bool { return i >= MaxBatchSize }},
+		{name: "first chunk", failSize: MaxBatchSize, failed: func(i int) bool { return i < MaxBatchSize }},
+		{name: "interceptor", failSize: 50, intercept: true, failed: func(i int) bool { return i >= MaxBatchSize }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+
+				var batch []testRPCRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+				if len(batch) == tt.failSize {
+					http.Error(w, "upstream failed", http.StatusBadGateway)
+					return
+				}
+
+				responses := make([]map[string]interface{}, len(batch))
+				for i, req := range batch {
+					responses[i] = map[string]interface{}{
+						"jsonrpc": "2.0",
+						"id":      req.ID,
+						"result":  map[string]string{"available": "1", "pending": "0", "total": "1"},
+					}
+				}
+				writeTestJSON(w, responses)
+			}))
+			t.Cleanup(server.Close)
+
+			config := &ClientConfig{Retry: &RetryPolicy{MaxAttempts: 1}}
+			if tt.intercept {
+				// Refuses the second batch without sending it
+				config.Interceptors = []Interceptor{
+					func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+						if batch, ok := params.([]BatchElem); ok && len(batch) == tt.failSize {
+							return ErrRateLimited
+						}
+						return next(ctx, method, params, result)
+					},
+				}
+			}
+			client := newTestClient(t, server, config)
+
+			balances, err := client.Wallet.GetBalances(context.Background(), addresses)
+			var batchErr *BatchError
+			require.ErrorAs(t, err, &batchErr)
+			require.Len(t, balances, len(addresses))
+			require.Len(t, batchErr.Errors, len(addresses))
+
+			for i := range addresses {
+				if tt.failed(i) {
+					assert.Nil(t, balances[i], i)
+					if tt.intercept {
+						assert.ErrorIs(t, batchErr.Errors[i], ErrRateLimited, i)
+					} else {
+						assert.ErrorIs(t, batchErr.Errors[i], ErrUnavailable, i)
+					}
+				} else {
+					require.NotNil(t, balances[i], i)
+					assert.Equal(t, "1", balances[i].Available.String())
+					assert.NoError(t, batchErr.Errors[i], i)
+				}
+			}
+
+			wantRequests := int32(2)
+			if tt.intercept {
+				wantRequests = 1
+			}
+			assert.Equal(t, wantRequests, atomic.LoadInt32(&requests))
+		})
+	}
+}
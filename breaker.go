@@ -0,0 +1,239 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by CircuitOpenError
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of an endpoint's circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets calls through and counts their failures
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails calls without sending them until the cool-down ends
+	CircuitOpen
+
+	// CircuitHalfOpen lets a few trial calls through; their outcome closes
+	// or reopens the circuit
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerOptions configures the circuit breaker kept for each
+// endpoint. Zero fields take the defaults from DefaultCircuitBreakerOptions.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is how many calls in a row must fail with transport
+	// errors, timeouts or 5xx statuses to open the circuit
+	FailureThreshold int
+
+	// CoolDown is how long the circuit stays open before trial calls are
+	// let through
+	CoolDown time.Duration
+
+	// HalfOpenRequests is how many trial calls may be in flight at once
+	// while half-open
+	HalfOpenRequests int
+
+	// OnStateChange, if set, is called whenever an endpoint's circuit
+	// changes state
+	OnStateChange func(endpoint string, from, to CircuitState)
+}
+
+// DefaultCircuitBreakerOptions returns the options used for zero
+// CircuitBreakerOptions fields
+func DefaultCircuitBreakerOptions() CircuitBreakerOptions {
+	return CircuitBreakerOptions{
+		FailureThreshold: 5,
+		CoolDown:         30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// withDefaults fills zero fields from DefaultCircuitBreakerOptions
+func (o *CircuitBreakerOptions) withDefaults() CircuitBreakerOptions {
+	defaults := DefaultCircuitBreakerOptions()
+	if o == nil {
+		return defaults
+	}
+
+	opts := *o
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaults.FailureThreshold
+	}
+	if opts.CoolDown <= 0 {
+		opts.CoolDown = defaults.CoolDown
+	}
+	if opts.HalfOpenRequests <= 0 {
+		opts.HalfOpenRequests = defaults.HalfOpenRequests
+	}
+	return opts
+}
+
+// CircuitOpenError is returned without sending a call when the circuit of
+// every usable endpoint is open
+type CircuitOpenError struct {
+	Endpoint string
+
+	// RetryAt is when trial calls will be let through again
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v for %s until %s", ErrCircuitOpen, e.Endpoint, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is makes errors.Is match ErrCircuitOpen and ErrUnavailable
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen || target == ErrUnavailable
+}
+
+// circuitBreaker tracks one endpoint. A nil breaker lets every call through.
+type circuitBreaker struct {
+	endpoint string
+	options  CircuitBreakerOptions
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func newCircuitBreaker(endpoint string, options CircuitBreakerOptions) *circuitBreaker {
+	return &circuitBreaker{endpoint: endpoint, options: options}
+}
+
+// ready reports whether allow would let a call through now
+func (b *circuitBreaker) ready(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		return !now.Before(b.openedAt.Add(b.options.CoolDown))
+	case CircuitHalfOpen:
+		return b.trials < b.options.HalfOpenRequests
+	default:
+		return true
+	}
+}
+
+// allow returns a *CircuitOpenError if a call must not be sent now. Every
+// call it lets through must be reported with record.
+func (b *circuitBreaker) allow(now time.Time) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	from := b.state
+	err := b.admit(now)
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return err
+}
+
+// admit is allow with b.mu held
+func (b *circuitBreaker) admit(now time.Time) error {
+	retryAt := b.openedAt.Add(b.options.CoolDown)
+	switch b.state {
+	case CircuitOpen:
+		if now.Before(retryAt) {
+			return &CircuitOpenError{Endpoint: b.endpoint, RetryAt: retryAt}
+		}
+		b.state = CircuitHalfOpen
+		b.trials = 1
+	case CircuitHalfOpen:
+		if b.trials >= b.options.HalfOpenRequests {
+			return &CircuitOpenError{Endpoint: b.endpoint, RetryAt: retryAt}
+		}
+		b.trials++
+	}
+	return nil
+}
+
+// record reports the outcome of a call let through by allow
+func (b *circuitBreaker) record(now time.Time, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	from := b.state
+	b.update(now, err)
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// update is record with b.mu held
+func (b *circuitBreaker) update(now time.Time, err error) {
+	if b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+
+	// A call cancelled by the caller says nothing about the endpoint
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if err == nil || !isEndpointFailure(err) {
+		b.failures = 0
+		if b.state == CircuitHalfOpen {
+			b.state = CircuitClosed
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.options.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = now
+		b.trials = 0
+	}
+}
+
+// currentState returns the state of the breaker
+func (b *circuitBreaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// notify reports a state change to the OnStateChange hook
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.options.OnStateChange != nil {
+		b.options.OnStateChange(b.endpoint, from, to)
+	}
+}
@@ -0,0 +1,154 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	type change struct{ from, to CircuitState }
+	var changes []change
+
+	b := newCircuitBreaker("node", (&CircuitBreakerOptions{
+		FailureThreshold: 3,
+		CoolDown:         time.Minute,
+		HalfOpenRequests: 2,
+		OnStateChange: func(endpoint string, from, to CircuitState) {
+			assert.Equal(t, "node", endpoint)
+			changes = append(changes, change{from, to})
+		},
+	}).withDefaults())
+
+	now := time.Now()
+	failure := &HTTPError{StatusCode: http.StatusBadGateway}
+
+	// Failures must come in a row to open the circuit
+	for _, err := range []error{failure, failure, nil, failure, failure} {
+		require.NoError(t, b.allow(now))
+		b.record(now, err)
+	}
+	assert.Equal(t, CircuitClosed, b.currentState())
+
+	// Client errors and cancelled calls do not count
+	for _, err := range []error{&HTTPError{StatusCode: http.StatusBadRequest}, &JSONRPCError{Code: -32000}, context.Canceled} {
+		require.NoError(t, b.allow(now))
+		b.record(now, err)
+	}
+	assert.Equal(t, CircuitClosed, b.currentState())
+
+	require.NoError(t, b.allow(now))
+	b.record(now, failure)
+	require.NoError(t, b.allow(now))
+	b.record(now, failure)
+	require.NoError(t, b.allow(now))
+	b.record(now, failure)
+	assert.Equal(t, CircuitOpen, b.currentState())
+
+	// Open: calls fail until the cool-down ends
+	err := b.allow(now.Add(30 * time.Second))
+	var openErr *CircuitOpenError
+	require.ErrorAs(t, err, &openErr)
+	assert.Equal(t, now.Add(time.Minute), openErr.RetryAt)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.False(t, b.ready(now.Add(30*time.Second)))
+
+	// Half-open: up to two trial calls at once
+	later := now.Add(time.Minute)
+	assert.True(t, b.ready(later))
+	require.NoError(t, b.allow(later))
+	assert.Equal(t, CircuitHalfOpen, b.currentState())
+	require.NoError(t, b.allow(later))
+	assert.ErrorIs(t, b.allow(later), ErrCircuitOpen)
+	assert.False(t, b.ready(later))
+
+	// A failed trial reopens the circuit
+	b.record(later, failure)
+	assert.Equal(t, CircuitOpen, b.currentState())
+	assert.ErrorIs(t, b.allow(later.Add(time.Second)), ErrCircuitOpen)
+
+	// A successful trial closes it
+	later = later.Add(time.Minute)
+	require.NoError(t, b.allow(later))
+	b.record(later, nil)
+	assert.Equal(t, CircuitClosed, b.currentState())
+
+	assert.Equal(t, []change{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}, changes)
+}
+
+func TestNilCircuitBreakerAllowsEverything(t *testing.T) {
+	var b *circuitBreaker
+	assert.NoError(t, b.allow(time.Now()))
+	assert.True(t, b.ready(time.Now()))
+	b.record(time.Now(), errors.New("failed"))
+	assert.Equal(t, CircuitClosed, b.currentState())
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var (
+		requests int32
+		healthy  atomic.Bool
+	)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		atomic.AddInt32(&requests, 1)
+		if !healthy.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		req.reply(w, 1)
+	})
+
+	var (
+		mu     sync.Mutex
+		states []CircuitState
+	)
+	client := newTestClient(t, server, &ClientConfig{
+		Retry: &RetryPolicy{MaxAttempts: 1},
+		CircuitBreaker: &CircuitBreakerOptions{
+			FailureThreshold: 2,
+			CoolDown:         50 * time.Millisecond,
+			OnStateChange: func(endpoint string, from, to CircuitState) {
+				mu.Lock()
+				states = append(states, to)
+				mu.Unlock()
+			},
+		},
+	})
+	call := func() error {
+		return client.RPC().Call(context.Background(), "getBlockHeight", nil, nil)
+	}
+
+	for i := 0; i < 2; i++ {
+		var httpErr *HTTPError
+		require.ErrorAs(t, call(), &httpErr)
+	}
+	assert.Equal(t, CircuitOpen, client.RPC().Endpoints()[0].Circuit)
+
+	// While open, calls fail without reaching the node
+	assert.ErrorIs(t, call(), ErrCircuitOpen)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, call())
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+	assert.Equal(t, CircuitClosed, client.RPC().Endpoints()[0].Circuit)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}, states)
+}
@@ -24,13 +24,12 @@
 package chert
 
 import (
-	"bytes"
 	"context"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
@@ -62,6 +61,18 @@ type ClientConfig struct {
 	// Endpoint is the API endpoint URL
 	Endpoint string `json:"endpoint"`
 
+	// Endpoints lists several nodes to spread calls across and fail over
+	// between. When set, Endpoint is ignored.
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Pool controls health checks and endpoint selection when Endpoints
+	// lists more than one node
+	Pool *PoolOptions `json:"-"`
+
+	// Quorum, if set, makes balance, block and transaction lookups ask
+	// every node in Endpoints and require agreement
+	Quorum *QuorumOptions `json:"-"`
+
 	// Network specifies the blockchain network
 	Network Network `json:"network"`
 
@@ -73,6 +84,43 @@ type ClientConfig struct {
 
 	// Headers contains additional HTTP headers
 	Headers map[string]string `json:"headers,omitempty"`
+
+	// HTTPClient, if set, is used for all requests, for example to
+	// configure mTLS or a proxy. Its Timeout is used if not zero.
+	HTTPClient *http.Client `json:"-"`
+
+	// Transport, if set, replaces the transport of HTTPClient or of the
+	// default client
+	Transport http.RoundTripper `json:"-"`
+
+	// RateLimit caps requests per second across all managers; zero means
+	// no limit
+	RateLimit float64 `json:"rate_limit,omitempty"`
+
+	// RateBurst is how many requests may exceed RateLimit at once; zero
+	// allows one second's worth
+	RateBurst int `json:"rate_burst,omitempty"`
+
+	// MaxInFlight caps the number of concurrent requests; zero means no limit
+	MaxInFlight int `json:"max_in_flight,omitempty"`
+
+	// CircuitBreaker, if set, keeps a circuit breaker per endpoint, so
+	// calls to a failing node fail fast instead of waiting for timeouts
+	CircuitBreaker *CircuitBreakerOptions `json:"-"`
+
+	// MaxResponseSize bounds response bodies, in bytes. Zero means
+	// DefaultMaxResponseSize.
+	MaxResponseSize int64 `json:"max_response_size,omitempty"`
+
+	// Hooks are called around every JSON-RPC call, with its request ID
+	Hooks *RPCHooks `json:"-"`
+
+	// Retry controls how failed calls are retried; nil uses
+	// DefaultRetryPolicy, which retries read-only methods only
+	Retry *RetryPolicy `json:"-"`
+
+	// Interceptors wrap every RPC call, outermost first
+	Interceptors []Interceptor `json:"-"`
 }
 
 // DefaultClientConfig returns a default client configuration
@@ -87,9 +135,8 @@ func DefaultClientConfig() *ClientConfig {
 
 // ChertClient is the main client for interacting with the Chert blockchain
 type ChertClient struct {
-	config     *ClientConfig
-	httpClient *http.Client
-	rpcClient  *RPCClient
+	config    *ClientConfig
+	rpcClient *RPCClient
 
 	// Managers
 	Wallet    *WalletManager
@@ -116,16 +163,30 @@ func NewClient(config *ClientConfig) (*ChertClient, error) {
 		config.Network = NetworkMainnet
 	}
 
-	httpClient := &http.Client{
-		Timeout: config.Timeout,
+	endpoints := config.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []string{config.Endpoint}
 	}
 
-	rpcClient := NewRPCClient(config.Endpoint, config.Timeout)
+	if config.Quorum != nil && config.Quorum.Required > len(endpoints) {
+		return nil, fmt.Errorf("quorum of %d is impossible with %d endpoints", config.Quorum.Required, len(endpoints))
+	}
+
+	rpcClient, err := newPooledRPCClient(endpoints, newHTTPClient(config), config.Pool)
+	if err != nil {
+		return nil, err
+	}
+	rpcClient.SetHooks(config.Hooks)
+	rpcClient.SetMaxResponseSize(config.MaxResponseSize)
+	rpcClient.SetLimits(config.RateLimit, config.RateBurst, config.MaxInFlight)
+	rpcClient.SetCircuitBreaker(config.CircuitBreaker)
+	rpcClient.SetRetryPolicy(config.Retry)
+	rpcClient.Use(config.Interceptors...)
+	rpcClient.startHealthChecks()
 
 	client := &ChertClient{
-		config:     config,
-		httpClient: httpClient,
-		rpcClient:  rpcClient,
+		config:    config,
+		rpcClient: rpcClient,
 	}
 
 	// Initialize managers
@@ -137,6 +198,22 @@ func NewClient(config *ClientConfig) (*ChertClient, error) {
 	return client, nil
 }
 
+// RPC returns the underlying JSON-RPC client, for calls the SDK has no
+// method for
+func (c *ChertClient) RPC() *RPCClient {
+	return c.rpcClient
+}
+
+// Endpoints returns the health of every endpoint the client uses
+func (c *ChertClient) Endpoints() []EndpointStatus {
+	return c.rpcClient.Endpoints()
+}
+
+// Close stops background work such as endpoint health checks
+func (c *ChertClient) Close() error {
+	return c.rpcClient.Close()
+}
+
 // GetNetworkStatus retrieves the current network status
 func (c *ChertClient) GetNetworkStatus(ctx context.Context) (*NetworkStatus, error) {
 	var result NetworkStatus
@@ -154,15 +231,30 @@ func (c *ChertClient) GetLatestBlock(ctx context.Context) (*Block, error) {
 // GetBlock retrieves block information by height
 func (c *ChertClient) GetBlock(ctx context.Context, height uint64) (*Block, error) {
 	var result Block
-	err := c.rpcClient.Call(ctx, "getBlock", []interface{}{height}, &result)
+	err := c.readCall(ctx, "getBlock", []interface{}{height}, &result)
 	return &result, err
 }
 
-// GetTransaction retrieves transaction information by hash
+// GetTransaction retrieves transaction information by hash. It returns
+// ErrTransactionNotFound (wrapped) if the node does not know the hash.
 func (c *ChertClient) GetTransaction(ctx context.Context, hash string) (*Transaction, error) {
 	var result Transaction
-	err := c.rpcClient.Call(ctx, "getTransaction", []interface{}{hash}, &result)
-	return &result, err
+	err := c.readCall(ctx, "getTransaction", []interface{}{hash}, &result)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s: %w", ErrTransactionNotFound, hash, err)
+		}
+		return nil, err
+	}
+
+	if result.Hash == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, hash)
+	}
+
+	if result.NetworkID == "" {
+		result.NetworkID = c.networkID()
+	}
+	return &result, nil
 }
 
 // IsConnected checks if the client is connected to the network
@@ -176,78 +268,9 @@ func (c *ChertClient) GetConfig() *ClientConfig {
 	return c.config
 }
 
-// makeRequest performs an HTTP request with proper error handling
-func (c *ChertClient) makeRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var bodyReader io.Reader
-	if body != nil {
-		bodyBytes, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		bodyReader = bytes.NewReader(bodyBytes)
-	}
-
-	url := c.config.Endpoint + path
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	// Add API key if provided
-	if c.config.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-	}
-
-	// Add custom headers
-	for key, value := range c.config.Headers {
-		req.Header.Set(key, value)
-	}
-
-	return c.httpClient.Do(req)
-}
-
-// handleResponse processes an HTTP response and unmarshals the result
-func (c *ChertClient) handleResponse(resp *http.Response, result interface{}) error {
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-		}
-		return &apiErr
-	}
-
-	var apiResp APIResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if !apiResp.Success {
-		if apiResp.Error != nil {
-			return apiResp.Error
-		}
-		return fmt.Errorf("API request failed")
-	}
-
-	if result != nil {
-		resultBytes, err := json.Marshal(apiResp.Data)
-		if err != nil {
-			return fmt.Errorf("failed to marshal result data: %w", err)
-		}
-		return json.Unmarshal(resultBytes, result)
-	}
-
-	return nil
+// networkID returns the network identifier used in canonical transaction encodings
+func (c *ChertClient) networkID() string {
+	return string(c.config.Network)
 }
 
 // APIResponse represents a standard API response
@@ -268,16 +291,19 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error %s: %s", e.Code, e.Message)
 }
 
-// GenerateAddress generates a deterministic address from a public key
-func GenerateAddress(publicKey string) (string, error) {
+// GenerateAddress generates a deterministic address on network from a public key
+func GenerateAddress(publicKey string, network Network) (string, error) {
 	pubKeyBytes, err := hex.DecodeString(publicKey)
 	if err != nil {
 		return "", fmt.Errorf("invalid public key hex: %w", err)
 	}
 
+	if len(pubKeyBytes) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("invalid public key length: expected %d bytes, got %d", ed25519.PublicKeySize, len(pubKeyBytes))
+	}
+
 	hash := sha256.Sum256(pubKeyBytes)
-	address := "chert_" + hex.EncodeToString(hash[:20])
-	return address, nil
+	return EncodeAddress(AddressKindStandard, network, hash[:AddressPayloadSize])
 }
 
 // GenerateTxID generates a new transaction ID
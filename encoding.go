@@ -0,0 +1,424 @@
+package chert
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// TxEncodingVersion is the version of the canonical transaction encoding
+// produced by EncodeTransaction. It is the first byte of every encoding.
+const TxEncodingVersion byte = 1
+
+// TransactionType identifies the operation encoded in a transaction
+type TransactionType byte
+
+const (
+	TxTypeTransfer       TransactionType = 1
+	TxTypeDelegate       TransactionType = 2
+	TxTypeUndelegate     TransactionType = 3
+	TxTypeClaimRewards   TransactionType = 4
+	TxTypeVote           TransactionType = 5
+	TxTypeCreateProposal TransactionType = 6
+
+	TxTypeRegisterValidator TransactionType = 7
+	TxTypeUpdateCommission  TransactionType = 8
+	TxTypeExecuteProposal   TransactionType = 9
+	TxTypeCancelProposal    TransactionType = 10
+)
+
+var transactionTypeNames = map[TransactionType]string{
+	TxTypeTransfer:       "transfer",
+	TxTypeDelegate:       "delegate",
+	TxTypeUndelegate:     "undelegate",
+	TxTypeClaimRewards:   "claim_rewards",
+	TxTypeVote:           "vote",
+	TxTypeCreateProposal: "create_proposal",
+
+	TxTypeRegisterValidator: "register_validator",
+	TxTypeUpdateCommission:  "update_commission",
+	TxTypeExecuteProposal:   "execute_proposal",
+	TxTypeCancelProposal:    "cancel_proposal",
+}
+
+func (t TransactionType) String() string {
+	if name, ok := transactionTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TransactionType(%d)", byte(t))
+}
+
+// MarshalText encodes the transaction type by name
+func (t TransactionType) MarshalText() ([]byte, error) {
+	name, ok := transactionTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction type %d", byte(t))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a transaction type from its name
+func (t *TransactionType) UnmarshalText(text []byte) error {
+	for txType, name := range transactionTypeNames {
+		if name == string(text) {
+			*t = txType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown transaction type %q", string(text))
+}
+
+// EncodeTransaction returns the canonical encoding of a transfer from sender
+// on the given network. These are the bytes that get signed, and their
+// SHA-256 digest is the transaction hash.
+func EncodeTransaction(networkID, sender string, request *TransactionRequest) ([]byte, error) {
+	if request == nil {
+		return nil, fmt.Errorf("transaction request is nil")
+	}
+
+	return newTransferTransaction(networkID, sender, request).Encode()
+}
+
+// ComputeTransactionHash returns the hex-encoded hash of a transfer from
+// sender on the given network, as the node will report it once broadcast
+func ComputeTransactionHash(networkID, sender string, request *TransactionRequest) (string, error) {
+	encoded, err := EncodeTransaction(networkID, sender, request)
+	if err != nil {
+		return "", err
+	}
+
+	return hashEncoding(encoded), nil
+}
+
+// ComputeHash recomputes the hash of the transaction from its fields,
+// encoding it according to its Type. NetworkID must be set; transactions
+// returned by GetTransaction have it filled in from the client
+// configuration.
+func (tx *Transaction) ComputeHash() (string, error) {
+	return tx.unsigned().Hash()
+}
+
+// unsigned returns the transaction as it was signed
+func (tx *Transaction) unsigned() *UnsignedTransaction {
+	txType := tx.Type
+	if txType == 0 {
+		txType = TxTypeTransfer
+	}
+
+	return &UnsignedTransaction{
+		Version:            TxEncodingVersion,
+		Type:               txType,
+		NetworkID:          tx.NetworkID,
+		From:               tx.From,
+		To:                 tx.To,
+		Amount:             tx.Amount,
+		Fee:                tx.Fee,
+		Nonce:              tx.Nonce,
+		Memo:               tx.Memo,
+		ProposalID:         tx.ProposalID,
+		Option:             tx.Option,
+		Title:              tx.Title,
+		Description:        tx.Description,
+		ValidatorName:      tx.ValidatorName,
+		ValidatorPublicKey: tx.ValidatorPublicKey,
+		CommissionRate:     tx.CommissionRate,
+		StakeAmount:        tx.StakeAmount,
+	}
+}
+
+// Encode returns the canonical encoding of the transaction.
+//
+// The layout is:
+//
+//	version   uint8
+//	type      uint8
+//	network   string
+//	from      string
+//	to        string
+//	amount    string, base units in decimal
+//	fee       string, base units in decimal
+//	nonce     uint64, big-endian
+//	memo      string
+//
+// followed by the fields specific to the transaction type:
+//
+//	vote                     proposal ID, option
+//	create proposal          title, description
+//	execute/cancel proposal  proposal ID
+//	register validator       name, public key, commission rate, stake
+//	update commission        commission rate
+//
+// Each string is its UTF-8 bytes prefixed with their length as an unsigned
+// varint; commission rates and stakes are uint64, big-endian.
+func (tx *UnsignedTransaction) Encode() ([]byte, error) {
+	if err := tx.validate(); err != nil {
+		return nil, err
+	}
+
+	var enc txEncoder
+	enc.byte(tx.Version)
+	enc.byte(byte(tx.Type))
+	enc.string(tx.NetworkID)
+	enc.string(tx.From)
+	enc.string(tx.To)
+	enc.string(tx.Amount.In(DenomNanoChert))
+	enc.string(tx.Fee.In(DenomNanoChert))
+	enc.uint64(tx.Nonce)
+	enc.string(tx.Memo)
+
+	switch tx.Type {
+	case TxTypeVote:
+		enc.string(tx.ProposalID)
+		enc.string(string(tx.Option))
+	case TxTypeCreateProposal:
+		enc.string(tx.Title)
+		enc.string(tx.Description)
+	case TxTypeExecuteProposal, TxTypeCancelProposal:
+		enc.string(tx.ProposalID)
+	case TxTypeRegisterValidator:
+		enc.string(tx.ValidatorName)
+		enc.string(tx.ValidatorPublicKey)
+		enc.uint64(uint64(tx.CommissionRate))
+		enc.uint64(tx.StakeAmount)
+	case TxTypeUpdateCommission:
+		enc.uint64(uint64(tx.CommissionRate))
+	}
+
+	return enc.buf, nil
+}
+
+// Hash returns the hex-encoded SHA-256 digest of the canonical encoding
+func (tx *UnsignedTransaction) Hash() (string, error) {
+	encoded, err := tx.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	return hashEncoding(encoded), nil
+}
+
+// DecodeTransaction parses a canonical transaction encoding as produced by
+// Encode. Encodings that are not canonical, such as ones with trailing bytes
+// or amounts with leading zeros, are rejected.
+func DecodeTransaction(encoded []byte) (*UnsignedTransaction, error) {
+	dec := txDecoder{buf: encoded}
+	tx := &UnsignedTransaction{
+		Version: dec.byte(),
+		Type:    TransactionType(dec.byte()),
+	}
+	if tx.Version != TxEncodingVersion {
+		return nil, fmt.Errorf("unsupported transaction encoding version %d", tx.Version)
+	}
+
+	tx.NetworkID = dec.string()
+	tx.From = dec.string()
+	tx.To = dec.string()
+	amount := dec.string()
+	fee := dec.string()
+	tx.Nonce = dec.uint64()
+	tx.Memo = dec.string()
+
+	switch tx.Type {
+	case TxTypeVote:
+		tx.ProposalID = dec.string()
+		tx.Option = VoteOption(dec.string())
+	case TxTypeCreateProposal:
+		tx.Title = dec.string()
+		tx.Description = dec.string()
+	case TxTypeExecuteProposal, TxTypeCancelProposal:
+		tx.ProposalID = dec.string()
+	case TxTypeRegisterValidator:
+		tx.ValidatorName = dec.string()
+		tx.ValidatorPublicKey = dec.string()
+		tx.CommissionRate = uint32(dec.uint64())
+		tx.StakeAmount = dec.uint64()
+	case TxTypeUpdateCommission:
+		tx.CommissionRate = uint32(dec.uint64())
+	}
+
+	if dec.err != nil {
+		return nil, fmt.Errorf("invalid transaction encoding: %w", dec.err)
+	}
+
+	var err error
+	if tx.Amount, err = ParseAmountIn(amount, DenomNanoChert); err != nil {
+		return nil, fmt.Errorf("invalid transaction amount: %w", err)
+	}
+	if tx.Fee, err = ParseAmountIn(fee, DenomNanoChert); err != nil {
+		return nil, fmt.Errorf("invalid transaction fee: %w", err)
+	}
+
+	reencoded, err := tx.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+	if !bytes.Equal(reencoded, encoded) {
+		return nil, fmt.Errorf("transaction encoding is not canonical")
+	}
+
+	return tx, nil
+}
+
+// validate checks the fields required by the transaction type
+func (tx *UnsignedTransaction) validate() error {
+	if tx.Version != TxEncodingVersion {
+		return fmt.Errorf("unsupported transaction encoding version %d", tx.Version)
+	}
+
+	if tx.NetworkID == "" {
+		return fmt.Errorf("network ID is required")
+	}
+
+	if tx.From == "" {
+		return fmt.Errorf("sender is required")
+	}
+
+	switch tx.Type {
+	case TxTypeTransfer:
+		if tx.To == "" {
+			return fmt.Errorf("recipient is required")
+		}
+		// Zero-value self-sends are allowed to cancel a pending transaction
+		if tx.Amount.Sign() < 0 || (tx.Amount.IsZero() && tx.To != tx.From) {
+			return fmt.Errorf("amount must be positive")
+		}
+	case TxTypeDelegate, TxTypeUndelegate:
+		if tx.To == "" {
+			return fmt.Errorf("validator address is required")
+		}
+		if tx.Amount.Sign() <= 0 {
+			return fmt.Errorf("amount must be positive")
+		}
+	case TxTypeClaimRewards:
+		if tx.To == "" {
+			return fmt.Errorf("validator address is required")
+		}
+	case TxTypeVote:
+		if tx.ProposalID == "" {
+			return fmt.Errorf("proposal ID is required")
+		}
+		if tx.Option == "" {
+			return fmt.Errorf("vote option is required")
+		}
+	case TxTypeCreateProposal:
+		if tx.Title == "" {
+			return fmt.Errorf("proposal title is required")
+		}
+	case TxTypeExecuteProposal, TxTypeCancelProposal:
+		if tx.ProposalID == "" {
+			return fmt.Errorf("proposal ID is required")
+		}
+	case TxTypeRegisterValidator:
+		if tx.ValidatorName == "" {
+			return fmt.Errorf("validator name is required")
+		}
+		if tx.ValidatorPublicKey == "" {
+			return fmt.Errorf("validator public key is required")
+		}
+	case TxTypeUpdateCommission:
+		if tx.To == "" {
+			return fmt.Errorf("validator address is required")
+		}
+	default:
+		return fmt.Errorf("unknown transaction type %d", byte(tx.Type))
+	}
+
+	if tx.Fee.Sign() < 0 {
+		return fmt.Errorf("fee must not be negative")
+	}
+
+	network := Network(tx.NetworkID)
+	if _, err := parseAddressOn(tx.From, network, AddressKindStandard); err != nil {
+		return fmt.Errorf("sender: %w", err)
+	}
+
+	if tx.To != "" {
+		// Transfers may pay stealth addresses; staking targets validators
+		if tx.Type == TxTypeTransfer {
+			if _, err := parseAddressOn(tx.To, network, ""); err != nil {
+				return fmt.Errorf("recipient: %w", err)
+			}
+		} else if _, err := parseAddressOn(tx.To, network, AddressKindStandard); err != nil {
+			return fmt.Errorf("validator address: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hashEncoding returns the hex-encoded SHA-256 digest of an encoding
+func hashEncoding(encoded []byte) string {
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:])
+}
+
+// txEncoder accumulates a canonical transaction encoding
+type txEncoder struct {
+	buf []byte
+}
+
+func (e *txEncoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *txEncoder) uint64(v uint64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+}
+
+func (e *txEncoder) string(s string) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// txDecoder reads a canonical transaction encoding. The first error is kept
+// in err and later reads return zero values.
+type txDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *txDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 1 {
+		d.err = fmt.Errorf("unexpected end of encoding")
+		return 0
+	}
+
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *txDecoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 8 {
+		d.err = fmt.Errorf("unexpected end of encoding")
+		return 0
+	}
+
+	v := binary.BigEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *txDecoder) string() string {
+	if d.err != nil {
+		return ""
+	}
+
+	length, n := binary.Uvarint(d.buf)
+	if n <= 0 || length > uint64(len(d.buf)-n) {
+		d.err = fmt.Errorf("invalid string length")
+		return ""
+	}
+
+	s := string(d.buf[n : n+int(length)])
+	d.buf = d.buf[n+int(length):]
+	return s
+}
@@ -0,0 +1,151 @@
+package chert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionComputeHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(tx *UnsignedTransaction)
+	}{
+		{"transfer", func(tx *UnsignedTransaction) {}},
+		{"delegate", func(tx *UnsignedTransaction) {
+			tx.Type = TxTypeDelegate
+		}},
+		{"vote", func(tx *UnsignedTransaction) {
+			tx.Type = TxTypeVote
+			tx.To = ""
+			tx.Amount = Amount{}
+			tx.ProposalID = "42"
+			tx.Option = VoteOptionNoWithVeto
+		}},
+		{"register validator", func(tx *UnsignedTransaction) {
+			tx.Type = TxTypeRegisterValidator
+			tx.To = testMainnetAddress
+			tx.Amount = Amount{}
+			tx.ValidatorName = "validator"
+			tx.ValidatorPublicKey = "6a8a1a8f"
+			tx.CommissionRate = 500
+			tx.StakeAmount = 1000
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsigned := newTestTransfer(t)
+			tt.modify(unsigned)
+			want, err := unsigned.Hash()
+			require.NoError(t, err)
+
+			// The node reports transactions with the fields they were signed with
+			data, err := json.Marshal(unsigned)
+			require.NoError(t, err)
+			var tx Transaction
+			require.NoError(t, json.Unmarshal(data, &tx))
+			tx.Hash = want
+
+			hash, err := tx.ComputeHash()
+			require.NoError(t, err)
+			assert.Equal(t, want, hash)
+		})
+	}
+}
+
+func TestTransactionComputeHashWithoutType(t *testing.T) {
+	unsigned := newTestTransfer(t)
+	want, err := unsigned.Hash()
+	require.NoError(t, err)
+
+	tx := Transaction{
+		From:      unsigned.From,
+		To:        unsigned.To,
+		Amount:    unsigned.Amount,
+		Fee:       unsigned.Fee,
+		Nonce:     unsigned.Nonce,
+		NetworkID: unsigned.NetworkID,
+	}
+	hash, err := tx.ComputeHash()
+	require.NoError(t, err)
+	assert.Equal(t, want, hash)
+
+	// A delegate with the same fields hashes differently
+	tx.Type = TxTypeDelegate
+	hash, err = tx.ComputeHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, want, hash)
+}
+
+func TestTransactionRejectsUnknownType(t *testing.T) {
+	var tx Transaction
+	assert.Error(t, json.Unmarshal([]byte(`{"type": "mint"}`), &tx))
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	transfer := newTestTransfer(t)
+	transfer.Memo = "invoice 12"
+
+	vote := newTestTransfer(t)
+	vote.Type = TxTypeVote
+	vote.To = ""
+	vote.Amount = NewAmount(0)
+	vote.ProposalID = "42"
+	vote.Option = VoteOptionYes
+
+	validator := newTestTransfer(t)
+	validator.Type = TxTypeRegisterValidator
+	validator.To = testMainnetAddress
+	validator.Amount = NewAmount(0)
+	validator.ValidatorName = "validator"
+	validator.ValidatorPublicKey = "6a8a1a8f"
+	validator.CommissionRate = 500
+	validator.StakeAmount = 1000
+
+	for _, tx := range []*UnsignedTransaction{transfer, vote, validator} {
+		encoded, err := tx.Encode()
+		require.NoError(t, err)
+
+		decoded, err := DecodeTransaction(encoded)
+		require.NoError(t, err, tx.Type)
+		assert.Equal(t, tx, decoded)
+	}
+
+	encoded, err := transfer.Encode()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		encoded []byte
+	}{
+		{"empty", nil},
+		{"truncated", encoded[:len(encoded)-1]},
+		{"trailing bytes", append(append([]byte{}, encoded...), 0)},
+		{"unknown version", append([]byte{2}, encoded[1:]...)},
+		{"unknown type", append([]byte{1, 99}, encoded[2:]...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTransaction(tt.encoded)
+			assert.Error(t, err)
+		})
+	}
+
+	// Amounts must be plain decimal base units
+	var enc txEncoder
+	enc.byte(TxEncodingVersion)
+	enc.byte(byte(TxTypeTransfer))
+	enc.string(transfer.NetworkID)
+	enc.string(transfer.From)
+	enc.string(transfer.To)
+	enc.string("0" + transfer.Amount.In(DenomNanoChert))
+	enc.string(transfer.Fee.In(DenomNanoChert))
+	enc.uint64(transfer.Nonce)
+	enc.string(transfer.Memo)
+	_, err = DecodeTransaction(enc.buf)
+	assert.EqualError(t, err, "transaction encoding is not canonical")
+}
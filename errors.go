@@ -0,0 +1,182 @@
+package chert
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors. Errors returned by the SDK wrap these, so they can be
+// checked with errors.Is whether they come from the SDK itself, a JSON-RPC
+// error, an HTTP status or an API error.
+var (
+	// ErrNotFound is returned when the requested object does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrTransactionNotFound is returned when the node does not know a
+	// transaction hash. It matches ErrNotFound.
+	ErrTransactionNotFound = fmt.Errorf("transaction %w", ErrNotFound)
+
+	// ErrTransactionFailed is returned when a transaction was included but
+	// failed, or was rejected by the node
+	ErrTransactionFailed = errors.New("transaction failed")
+
+	// ErrWaitTimeout is returned when a transaction is not confirmed within
+	// the wait timeout
+	ErrWaitTimeout = errors.New("timed out waiting for transaction")
+
+	// ErrInsufficientFunds is returned when the sender cannot pay the amount
+	// and fee of a transaction
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrNonceTooLow is returned when a transaction reuses a nonce the
+	// account has already used
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	// ErrBroadcastUnknown is returned when a broadcast failed in a way that
+	// leaves open whether the node accepted the transaction, such as a
+	// timeout. Look the transaction up before sending it again.
+	ErrBroadcastUnknown = errors.New("broadcast outcome unknown")
+
+	// ErrInvalidSignature is returned when a transaction signature does not
+	// verify against its public key
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrFeeTooLow is returned when a transaction fee is below the network minimum
+	ErrFeeTooLow = errors.New("fee too low")
+
+	// ErrReplacementUnderpriced is returned when a replacement transaction
+	// does not pay a higher fee than the transaction it replaces
+	ErrReplacementUnderpriced = errors.New("replacement fee must exceed the original fee")
+
+	// ErrValidatorJailed is returned when a validator is jailed and cannot
+	// take delegations or produce blocks
+	ErrValidatorJailed = errors.New("validator is jailed")
+
+	// ErrRateLimited is returned when the node or gateway throttles the client
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnauthorized is returned when the API key is missing or rejected
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrMethodNotFound is returned when the node does not support a method
+	ErrMethodNotFound = errors.New("method not found")
+
+	// ErrInvalidRequest is returned when the node cannot parse a request
+	ErrInvalidRequest = errors.New("invalid request")
+
+	// ErrInvalidParams is returned when the node rejects the parameters of
+	// a call
+	ErrInvalidParams = errors.New("invalid params")
+
+	// ErrInternal is returned when the node fails while handling a call
+	ErrInternal = errors.New("internal node error")
+
+	// ErrUnavailable is returned when the node or a proxy in front of it
+	// cannot handle requests at the moment
+	ErrUnavailable = errors.New("node unavailable")
+
+	// ErrInvalidResponse is returned when a response cannot be used, for
+	// example because a required field is missing
+	ErrInvalidResponse = errors.New("invalid response")
+
+	// ErrResponseIDMismatch is returned when a JSON-RPC response does not
+	// carry the ID of the request it answers. It matches ErrInvalidResponse.
+	ErrResponseIDMismatch = fmt.Errorf("%w: JSON-RPC response ID does not match the request", ErrInvalidResponse)
+)
+
+// rpcErrorCodes maps the error codes defined by JSON-RPC 2.0
+var rpcErrorCodes = map[int]error{
+	-32700: ErrInvalidRequest,
+	-32600: ErrInvalidRequest,
+	-32601: ErrMethodNotFound,
+	-32602: ErrInvalidParams,
+	-32603: ErrInternal,
+}
+
+// rpcErrorMessages maps the node's own errors, which are identified by
+// their message, in order of precedence
+var rpcErrorMessages = []struct {
+	text string
+	err  error
+}{
+	{"insufficient funds", ErrInsufficientFunds},
+	{"insufficient balance", ErrInsufficientFunds},
+	{"nonce too low", ErrNonceTooLow},
+	{"replacement transaction underpriced", ErrReplacementUnderpriced},
+	{"fee too low", ErrFeeTooLow},
+	{"invalid signature", ErrInvalidSignature},
+	{"jailed", ErrValidatorJailed},
+	{"rate limit", ErrRateLimited},
+	{"too many requests", ErrRateLimited},
+	{"unauthorized", ErrUnauthorized},
+	{"not found", ErrNotFound},
+}
+
+// httpStatusErrors maps HTTP statuses
+var httpStatusErrors = map[int]error{
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrUnauthorized,
+	http.StatusTooManyRequests:     ErrRateLimited,
+	http.StatusInternalServerError: ErrInternal,
+	http.StatusBadGateway:          ErrUnavailable,
+	http.StatusServiceUnavailable:  ErrUnavailable,
+	http.StatusGatewayTimeout:      ErrUnavailable,
+}
+
+// apiErrorCodes maps the string codes of API errors
+var apiErrorCodes = map[string]error{
+	"not_found":          ErrNotFound,
+	"insufficient_funds": ErrInsufficientFunds,
+	"nonce_too_low":      ErrNonceTooLow,
+	"fee_too_low":        ErrFeeTooLow,
+	"invalid_signature":  ErrInvalidSignature,
+	"validator_jailed":   ErrValidatorJailed,
+	"rate_limited":       ErrRateLimited,
+	"unauthorized":       ErrUnauthorized,
+	"forbidden":          ErrUnauthorized,
+	"invalid_request":    ErrInvalidRequest,
+	"invalid_params":     ErrInvalidParams,
+	"internal_error":     ErrInternal,
+	"unavailable":        ErrUnavailable,
+}
+
+// Is reports whether e matches target. Standard JSON-RPC codes are matched
+// by code and the node's own errors by message.
+func (e *JSONRPCError) Is(target error) bool {
+	if sentinel, ok := rpcErrorCodes[e.Code]; ok {
+		return target == sentinel
+	}
+
+	message := strings.ToLower(e.Message)
+	for _, known := range rpcErrorMessages {
+		if target == known.err && strings.Contains(message, known.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Is reports whether e matches target, by status
+func (e *HTTPError) Is(target error) bool {
+	sentinel, ok := httpStatusErrors[e.StatusCode]
+	return ok && target == sentinel
+}
+
+// Is reports whether e matches target, by code
+func (e *APIError) Is(target error) bool {
+	code := strings.ToLower(strings.NewReplacer("-", "_", " ", "_").Replace(e.Code))
+	sentinel, ok := apiErrorCodes[code]
+	return ok && target == sentinel
+}
+
+// invalidResponse returns an *RPCError for a response to a successful call
+// that lacks what the SDK needs
+func invalidResponse(method string, requestID uint64, format string, args ...interface{}) error {
+	return &RPCError{
+		Method:    method,
+		RequestID: requestID,
+		Err:       fmt.Errorf("%w: %s", ErrInvalidResponse, fmt.Sprintf(format, args...)),
+	}
+}
@@ -0,0 +1,147 @@
+package chert
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSentinels are the sentinels the Is tables check against, so that each
+// case also asserts that no other sentinel matches
+var testSentinels = []error{
+	ErrNotFound,
+	ErrInsufficientFunds,
+	ErrNonceTooLow,
+	ErrFeeTooLow,
+	ErrInvalidSignature,
+	ErrReplacementUnderpriced,
+	ErrValidatorJailed,
+	ErrRateLimited,
+	ErrUnauthorized,
+	ErrMethodNotFound,
+	ErrInvalidRequest,
+	ErrInvalidParams,
+	ErrInternal,
+	ErrUnavailable,
+}
+
+// assertSentinel checks that err matches want, also when wrapped, and no
+// other sentinel; a nil want means none
+func assertSentinel(t *testing.T, err error, want error) {
+	t.Helper()
+
+	wrapped := &RPCError{Method: "getBalance", Err: fmt.Errorf("call failed: %w", err)}
+	for _, sentinel := range testSentinels {
+		if sentinel == want {
+			assert.ErrorIs(t, err, sentinel)
+			assert.ErrorIs(t, wrapped, sentinel)
+		} else {
+			assert.NotErrorIs(t, err, sentinel)
+		}
+	}
+}
+
+func TestJSONRPCErrorIs(t *testing.T) {
+	tests := []struct {
+		code    int
+		message string
+		want    error
+	}{
+		{code: -32700, message: "parse error", want: ErrInvalidRequest},
+		{code: -32600, message: "invalid request", want: ErrInvalidRequest},
+		{code: -32601, message: "method not found", want: ErrMethodNotFound},
+		{code: -32602, message: "invalid params", want: ErrInvalidParams},
+		{code: -32603, message: "internal error", want: ErrInternal},
+
+		// Standard codes win over the message
+		{code: -32602, message: "account not found", want: ErrInvalidParams},
+
+		{code: -32000, message: "Insufficient funds for transfer", want: ErrInsufficientFunds},
+		{code: -32000, message: "insufficient balance", want: ErrInsufficientFunds},
+		{code: -32000, message: "nonce too low: next nonce 8", want: ErrNonceTooLow},
+		{code: -32000, message: "replacement transaction underpriced", want: ErrReplacementUnderpriced},
+		{code: -32000, message: "fee too low", want: ErrFeeTooLow},
+		{code: -32000, message: "invalid signature", want: ErrInvalidSignature},
+		{code: -32000, message: "validator is jailed", want: ErrValidatorJailed},
+		{code: -32005, message: "rate limit exceeded", want: ErrRateLimited},
+		{code: -32005, message: "Too Many Requests", want: ErrRateLimited},
+		{code: -32001, message: "unauthorized", want: ErrUnauthorized},
+		{code: -32004, message: "block not found", want: ErrNotFound},
+		{code: -32000, message: "something else went wrong"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			assertSentinel(t, &JSONRPCError{Code: tt.code, Message: tt.message}, tt.want)
+		})
+	}
+
+	assert.ErrorIs(t, &JSONRPCError{Code: -32004, Message: "transaction not found"}, ErrNotFound)
+	assert.False(t, errors.Is(&JSONRPCError{Code: -32004, Message: "transaction not found"}, ErrTransactionNotFound),
+		"the node's not-found is generic; GetTransaction narrows it")
+}
+
+func TestHTTPErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{status: http.StatusUnauthorized, want: ErrUnauthorized},
+		{status: http.StatusForbidden, want: ErrUnauthorized},
+		{status: http.StatusTooManyRequests, want: ErrRateLimited},
+		{status: http.StatusInternalServerError, want: ErrInternal},
+		{status: http.StatusBadGateway, want: ErrUnavailable},
+		{status: http.StatusServiceUnavailable, want: ErrUnavailable},
+		{status: http.StatusGatewayTimeout, want: ErrUnavailable},
+		{status: http.StatusBadRequest},
+		{status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			assertSentinel(t, &HTTPError{StatusCode: tt.status}, tt.want)
+		})
+	}
+
+	// A JSON-RPC error in the body is matched too
+	err := &HTTPError{StatusCode: http.StatusBadRequest, Err: &JSONRPCError{Code: -32602, Message: "invalid params"}}
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		code string
+		want error
+	}{
+		{code: "not_found", want: ErrNotFound},
+		{code: "insufficient_funds", want: ErrInsufficientFunds},
+		{code: "nonce_too_low", want: ErrNonceTooLow},
+		{code: "fee_too_low", want: ErrFeeTooLow},
+		{code: "invalid_signature", want: ErrInvalidSignature},
+		{code: "validator_jailed", want: ErrValidatorJailed},
+		{code: "rate_limited", want: ErrRateLimited},
+		{code: "unauthorized", want: ErrUnauthorized},
+		{code: "forbidden", want: ErrUnauthorized},
+		{code: "invalid_request", want: ErrInvalidRequest},
+		{code: "invalid_params", want: ErrInvalidParams},
+		{code: "internal_error", want: ErrInternal},
+		{code: "unavailable", want: ErrUnavailable},
+
+		// Codes are normalised
+		{code: "NOT_FOUND", want: ErrNotFound},
+		{code: "nonce-too-low", want: ErrNonceTooLow},
+		{code: "Rate Limited", want: ErrRateLimited},
+
+		{code: "conflict"},
+		{code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assertSentinel(t, &APIError{Code: tt.code, Message: "message"}, tt.want)
+		})
+	}
+}
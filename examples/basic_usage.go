@@ -142,9 +142,9 @@ func main() {
 	// Estimate transaction fee
 	fmt.Println("\n💸 Estimating transaction fee...")
 	txRequest := &chert.TransactionRequest{
-		To:     "chert_1example_address_1234567890",
-		Amount: "100.0",
-		Fee:    "0.1",
+		To:     "chert1y8lrrhap2j3xzcntlp2qgm7jyudhhm2t94a7ec",
+		Amount: chert.MustParseAmount("100.0"),
+		Fee:    chert.MustParseAmount("0.1"),
 		Memo:   "Chert SDK Go Example Transaction",
 	}
 
@@ -186,8 +186,8 @@ func offlineExamples(client *chert.ChertClient) {
 	fmt.Println("\n📝 Creating transaction request...")
 	txRequest := &chert.TransactionRequest{
 		To:     account2.Address,
-		Amount: "50.0",
-		Fee:    "0.05",
+		Amount: chert.MustParseAmount("50.0"),
+		Fee:    chert.MustParseAmount("0.05"),
 		Memo:   "Offline example transaction",
 	}
 
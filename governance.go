@@ -6,6 +6,11 @@ import (
 )
 
 // GovernanceManager handles governance operations and proposals
+//
+// Methods that send a transaction sign it with the nonce GetNonce reports
+// for the signer. To have several transactions from one account in flight
+// at once, build them with the Build methods and send them through a
+// NonceManager instead.
 type GovernanceManager struct {
 	client *ChertClient
 }
@@ -36,48 +41,70 @@ func (gm *GovernanceManager) GetProposal(ctx context.Context, proposalID string)
 	return &result, err
 }
 
-// CreateProposal creates a new governance proposal
-func (gm *GovernanceManager) CreateProposal(ctx context.Context, title, description, proposerAddress, fee string) (string, error) {
-	params := map[string]interface{}{
-		"title":       title,
-		"description": description,
-		"proposer":    proposerAddress,
-		"fee":         fee,
+// CreateProposal creates a new governance proposal and returns its ID
+func (gm *GovernanceManager) CreateProposal(ctx context.Context, signer Signer, title, description string, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := gm.client.rpcClient.Call(ctx, "governance_createProposal", []interface{}{params}, &result)
+	nonce, err := gm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if proposalID, ok := result["proposal_id"].(string); ok {
-		return proposalID, nil
+	tx, err := gm.BuildCreateProposal(title, description, signer.Address(), fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid proposal creation response")
+	return gm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildCreateProposal builds an unsigned proposal with the given nonce for offline signing
+func (gm *GovernanceManager) BuildCreateProposal(title, description, proposerAddress string, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:     TxEncodingVersion,
+		Type:        TxTypeCreateProposal,
+		NetworkID:   gm.client.networkID(),
+		From:        proposerAddress,
+		Fee:         fee,
+		Nonce:       nonce,
+		Title:       title,
+		Description: description,
+	})
 }
 
 // Vote casts a vote on a governance proposal
-func (gm *GovernanceManager) Vote(ctx context.Context, proposalID, voterAddress string, option VoteOption, fee string) (string, error) {
-	params := map[string]interface{}{
-		"proposal_id": proposalID,
-		"voter":       voterAddress,
-		"option":      option,
-		"fee":         fee,
+func (gm *GovernanceManager) Vote(ctx context.Context, signer Signer, proposalID string, option VoteOption, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := gm.client.rpcClient.Call(ctx, "governance_vote", []interface{}{params}, &result)
+	nonce, err := gm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := gm.BuildVote(proposalID, signer.Address(), option, fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid vote response")
+	return gm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildVote builds an unsigned vote with the given nonce for offline signing
+func (gm *GovernanceManager) BuildVote(proposalID, voterAddress string, option VoteOption, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:    TxEncodingVersion,
+		Type:       TxTypeVote,
+		NetworkID:  gm.client.networkID(),
+		From:       voterAddress,
+		Fee:        fee,
+		Nonce:      nonce,
+		ProposalID: proposalID,
+		Option:     option,
+	})
 }
 
 // GetProposalVotes retrieves votes for a specific proposal
@@ -95,45 +122,67 @@ func (gm *GovernanceManager) GetVoterVotes(ctx context.Context, voterAddress str
 }
 
 // ExecuteProposal executes a passed proposal (admin function)
-func (gm *GovernanceManager) ExecuteProposal(ctx context.Context, proposalID, executorAddress, fee string) (string, error) {
-	params := map[string]interface{}{
-		"proposal_id": proposalID,
-		"executor":    executorAddress,
-		"fee":         fee,
+func (gm *GovernanceManager) ExecuteProposal(ctx context.Context, signer Signer, proposalID string, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := gm.client.rpcClient.Call(ctx, "governance_executeProposal", []interface{}{params}, &result)
+	nonce, err := gm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := gm.BuildExecuteProposal(proposalID, signer.Address(), fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid proposal execution response")
+	return gm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildExecuteProposal builds an unsigned proposal execution with the given nonce for offline signing
+func (gm *GovernanceManager) BuildExecuteProposal(proposalID, executorAddress string, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:    TxEncodingVersion,
+		Type:       TxTypeExecuteProposal,
+		NetworkID:  gm.client.networkID(),
+		From:       executorAddress,
+		Fee:        fee,
+		Nonce:      nonce,
+		ProposalID: proposalID,
+	})
 }
 
 // CancelProposal cancels a proposal (only by proposer)
-func (gm *GovernanceManager) CancelProposal(ctx context.Context, proposalID, proposerAddress, fee string) (string, error) {
-	params := map[string]interface{}{
-		"proposal_id": proposalID,
-		"proposer":    proposerAddress,
-		"fee":         fee,
+func (gm *GovernanceManager) CancelProposal(ctx context.Context, signer Signer, proposalID string, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := gm.client.rpcClient.Call(ctx, "governance_cancelProposal", []interface{}{params}, &result)
+	nonce, err := gm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := gm.BuildCancelProposal(proposalID, signer.Address(), fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid proposal cancellation response")
+	return gm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildCancelProposal builds an unsigned proposal cancellation with the given nonce for offline signing
+func (gm *GovernanceManager) BuildCancelProposal(proposalID, proposerAddress string, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:    TxEncodingVersion,
+		Type:       TxTypeCancelProposal,
+		NetworkID:  gm.client.networkID(),
+		From:       proposerAddress,
+		Fee:        fee,
+		Nonce:      nonce,
+		ProposalID: proposalID,
+	})
 }
 
 // GetProposalStatus retrieves the current status of a proposal
@@ -146,9 +195,9 @@ func (gm *GovernanceManager) GetProposalStatus(ctx context.Context, proposalID s
 }
 
 // GetVotingPower retrieves the voting power of an address
-func (gm *GovernanceManager) GetVotingPower(ctx context.Context, address string) (string, error) {
+func (gm *GovernanceManager) GetVotingPower(ctx context.Context, address string) (Amount, error) {
 	var result struct {
-		VotingPower string `json:"voting_power"`
+		VotingPower Amount `json:"voting_power"`
 	}
 	err := gm.client.rpcClient.Call(ctx, "governance_getVotingPower", []interface{}{address}, &result)
 	return result.VotingPower, err
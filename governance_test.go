@@ -0,0 +1,60 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteUsesNextNonce(t *testing.T) {
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	fee := MustParseAmount("0.01")
+
+	var client *ChertClient
+	server, sent := newTestNonceServer(t, 0, "governance_vote", func(nonce uint64) string {
+		tx, err := client.Governance.BuildVote("7", signer.Address(), VoteOptionYes, fee, nonce)
+		require.NoError(t, err)
+		hash, err := tx.Hash()
+		require.NoError(t, err)
+		return hash
+	})
+	client = newTestClient(t, server, nil)
+
+	var hashes []string
+	for i := 0; i < 3; i++ {
+		hash, err := client.Governance.Vote(context.Background(), signer, "7", VoteOptionYes, fee)
+		require.NoError(t, err)
+		hashes = append(hashes, hash)
+	}
+
+	assert.Equal(t, []uint64{0, 1, 2}, *sent)
+	assert.Len(t, map[string]bool{hashes[0]: true, hashes[1]: true, hashes[2]: true}, 3)
+}
+
+func TestGetVotingPower(t *testing.T) {
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		var address string
+		req.param(t, &address)
+		switch address {
+		case testMainnetAddress:
+			req.reply(w, map[string]string{"voting_power": "98765432109876.000000001"})
+		case testValidatorAddress:
+			req.reply(w, map[string]string{"voting_power": "lots"})
+		default:
+			req.fail(w, -32000, "account not found")
+		}
+	})
+	client := newTestClient(t, server, nil)
+
+	power, err := client.Governance.GetVotingPower(context.Background(), testMainnetAddress)
+	require.NoError(t, err)
+	assert.Zero(t, MustParseAmount("98765432109876.000000001").Cmp(power), "got %s", power)
+
+	_, err = client.Governance.GetVotingPower(context.Background(), testValidatorAddress)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
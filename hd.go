@@ -0,0 +1,219 @@
+package chert
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HardenedOffset is added to a child index to mark it hardened
+	HardenedOffset uint32 = 0x80000000
+
+	// CoinType is the SLIP-44 coin type used in Chert derivation paths
+	CoinType uint32 = 7337
+)
+
+// DerivationPath is a sequence of child indexes from the master key. SLIP-10
+// Ed25519 derivation only supports hardened indexes.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses a path such as m/44'/7337'/0'/0'/0'. Every
+// component must be hardened, marked with ' or h.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+
+	result := make(DerivationPath, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		trimmed := strings.TrimRight(part, "'h")
+		if trimmed == part || len(part)-len(trimmed) != 1 {
+			return nil, fmt.Errorf("invalid derivation path %q: component %q must be a hardened index such as 0'", path, part)
+		}
+
+		index, err := strconv.ParseUint(trimmed, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %q: bad index %q", path, part)
+		}
+
+		result = append(result, uint32(index)+HardenedOffset)
+	}
+
+	return result, nil
+}
+
+// DefaultDerivationPath returns m/44'/CoinType'/account'/0'/index'
+func DefaultDerivationPath(account, index uint32) DerivationPath {
+	return DerivationPath{
+		44 + HardenedOffset,
+		CoinType + HardenedOffset,
+		account + HardenedOffset,
+		HardenedOffset,
+		index + HardenedOffset,
+	}
+}
+
+func (p DerivationPath) String() string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range p {
+		fmt.Fprintf(&b, "/%d'", index-HardenedOffset)
+	}
+	return b.String()
+}
+
+// HDKey is a SLIP-10 Ed25519 extended private key
+type HDKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// NewMasterKey derives the SLIP-10 Ed25519 master key from a seed, such as
+// the 64-byte seed returned by MnemonicToSeed
+func NewMasterKey(seed []byte) (*HDKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("invalid seed length: expected 16 to 64 bytes, got %d", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	return &HDKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// Child derives the hardened child key at index. Indexes below
+// HardenedOffset are rejected because Ed25519 has no public derivation.
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	if index < HardenedOffset {
+		return nil, fmt.Errorf("ed25519 derivation requires hardened indexes, got %d", index)
+	}
+
+	data := make([]byte, 0, 37)
+	data = append(data, 0)
+	data = append(data, k.key...)
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	return &HDKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// Derive derives the key at path relative to k
+func (k *HDKey) Derive(path DerivationPath) (*HDKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+// PrivateKey returns the hex-encoded Ed25519 private key (seed)
+func (k *HDKey) PrivateKey() string {
+	return hex.EncodeToString(k.key)
+}
+
+// PublicKey returns the hex-encoded Ed25519 public key
+func (k *HDKey) PublicKey() string {
+	return hex.EncodeToString(ed25519.NewKeyFromSeed(k.key).Public().(ed25519.PublicKey))
+}
+
+// ChainCode returns the hex-encoded chain code
+func (k *HDKey) ChainCode() string {
+	return hex.EncodeToString(k.chainCode)
+}
+
+// HDWallet derives accounts from a single master key
+type HDWallet struct {
+	wallet *WalletManager
+	master *HDKey
+}
+
+// NewHDWallet creates an HD wallet from a seed
+func (wm *WalletManager) NewHDWallet(seed []byte) (*HDWallet, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HDWallet{wallet: wm, master: master}, nil
+}
+
+// NewHDWalletFromMnemonic creates an HD wallet from a BIP39 mnemonic and an
+// optional passphrase
+func (wm *WalletManager) NewHDWalletFromMnemonic(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return wm.NewHDWallet(seed)
+}
+
+// DeriveAccount derives the account at a path such as m/44'/7337'/0'/0'/5'
+func (w *HDWallet) DeriveAccount(path string) (*Account, error) {
+	parsed, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.DeriveAccountAt(parsed)
+}
+
+// DeriveAccountAt derives the account at a parsed path
+func (w *HDWallet) DeriveAccountAt(path DerivationPath) (*Account, error) {
+	key, err := w.master.Derive(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+	}
+
+	return w.wallet.ImportAccount(key.PrivateKey())
+}
+
+// DeriveAccounts derives count consecutive accounts starting at index
+// start under DefaultDerivationPath(account, ...)
+func (w *HDWallet) DeriveAccounts(account, start, count uint32) ([]*Account, error) {
+	if uint64(start)+uint64(count) > uint64(HardenedOffset) {
+		return nil, fmt.Errorf("index range %d+%d exceeds the hardened index space", start, count)
+	}
+
+	accounts := make([]*Account, 0, count)
+	for i := uint32(0); i < count; i++ {
+		derived, err := w.DeriveAccountAt(DefaultDerivationPath(account, start+i))
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, derived)
+	}
+
+	return accounts, nil
+}
+
+// Addresses returns the addresses of count consecutive accounts starting at
+// index start under DefaultDerivationPath(account, ...)
+func (w *HDWallet) Addresses(account, start, count uint32) ([]string, error) {
+	accounts, err := w.DeriveAccounts(account, start, count)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, len(accounts))
+	for i, derived := range accounts {
+		addresses[i] = derived.Address
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,112 @@
+package chert
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSLIP10Vector1 checks test vector 1 for ed25519 from SLIP-0010
+func TestSLIP10Vector1(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path       string
+		privateKey string
+		chainCode  string
+	}{
+		{"m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb"},
+		{"m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69"},
+		{"m/0'/1'", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2", "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14"},
+		{"m/0'/1'/2'", "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9", "2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c"},
+		{"m/0'/1'/2'/2'", "30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662", "8f6d87f93d750e0efccda017d662a1b31a266e4a6f5993b15f5c1f07f74dd5cc"},
+		{"m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793", "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := ParseDerivationPath(tt.path)
+			require.NoError(t, err)
+
+			key, err := master.Derive(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.privateKey, key.PrivateKey())
+			assert.Equal(t, tt.chainCode, key.ChainCode())
+		})
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    DerivationPath
+		wantErr bool
+	}{
+		{path: "m", want: DerivationPath{}},
+		{path: "m/44'/7337'/0'/0'/5'", want: DefaultDerivationPath(0, 5)},
+		{path: "m/44h/7337h/2h/0h/1h", want: DefaultDerivationPath(2, 1)},
+		{path: "m/44'/7337'/0'/0/0'", wantErr: true},
+		{path: "m/44''", wantErr: true},
+		{path: "44'/7337'", wantErr: true},
+		{path: "", wantErr: true},
+		{path: "m/x'", wantErr: true},
+		{path: "m/2147483648'", wantErr: true},
+		{path: "m//0'", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := ParseDerivationPath(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, path)
+		})
+	}
+
+	assert.Equal(t, "m/44'/7337'/3'/0'/7'", DefaultDerivationPath(3, 7).String())
+}
+
+func TestHDKeyRejectsBadInput(t *testing.T) {
+	_, err := NewMasterKey(make([]byte, 15))
+	assert.Error(t, err)
+	_, err = NewMasterKey(make([]byte, 65))
+	assert.Error(t, err)
+
+	master, err := NewMasterKey(make([]byte, 32))
+	require.NoError(t, err)
+	_, err = master.Child(0)
+	assert.Error(t, err, "non-hardened index")
+}
+
+func TestHDWalletAccounts(t *testing.T) {
+	wallet := newTestWallet(t)
+
+	hd, err := wallet.NewHDWalletFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+
+	accounts, err := hd.DeriveAccounts(1, 10, 3)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+
+	addresses, err := hd.Addresses(1, 10, 3)
+	require.NoError(t, err)
+
+	for i, account := range accounts {
+		derived, err := hd.DeriveAccountAt(DefaultDerivationPath(1, 10+uint32(i)))
+		require.NoError(t, err)
+		assert.Equal(t, derived, account)
+		assert.Equal(t, account.Address, addresses[i])
+	}
+
+	_, err = hd.DeriveAccounts(0, HardenedOffset-1, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,260 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BatchMethod is the method interceptors see for BatchCall. Its params are
+// the []BatchElem and its result is nil.
+const BatchMethod = "batch"
+
+// Invoker sends a call, or passes it on to the next interceptor
+type Invoker func(ctx context.Context, method string, params interface{}, result interface{}) error
+
+// Interceptor wraps every call made through an RPCClient. It may inspect or
+// change the method, params and context before calling next, inspect or
+// change the result and error afterwards, call next several times, or not
+// call it at all and answer the call itself.
+type Interceptor func(ctx context.Context, method string, params interface{}, result interface{}, next Invoker) error
+
+// Use appends interceptors to the client's chain. The first interceptor
+// added is the outermost; the client's retry policy always runs innermost,
+// so interceptors see each call once however often it is retried. Health
+// checks of pooled endpoints pass through them too, as getNetworkStatus
+// calls that are never retried. Call it before the client is used.
+func (c *RPCClient) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// chain returns the invoker for a call: the interceptors, then the retry
+// policy if retry is set, then a single attempt
+func (c *RPCClient) chain(retry bool) Invoker {
+	next := Invoker(c.invoke)
+	if retry {
+		next = intercept(RetryInterceptor(&c.retry), next)
+	}
+	return c.intercepted(next)
+}
+
+// intercepted wraps next in the client's interceptors
+func (c *RPCClient) intercepted(next Invoker) Invoker {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		next = intercept(c.interceptors[i], next)
+	}
+	return next
+}
+
+// intercept binds an interceptor to the invoker it wraps
+func intercept(interceptor Interceptor, next Invoker) Invoker {
+	return func(ctx context.Context, method string, params interface{}, result interface{}) error {
+		return interceptor(ctx, method, params, result, next)
+	}
+}
+
+// callState is shared by the attempts of one call through the context
+type callState struct {
+	mu sync.Mutex
+
+	// tried holds the endpoints already used, so retries go elsewhere
+	tried map[*endpoint]bool
+
+	// pinned, if set, is the only endpoint the call may use
+	pinned *endpoint
+
+	// requestID is the ID of the last request sent
+	requestID uint64
+}
+
+type callStateKey struct{}
+
+// withCallState returns a context carrying fresh state for one call
+func withCallState(ctx context.Context, pinned *endpoint) (context.Context, *callState) {
+	state := &callState{tried: make(map[*endpoint]bool), pinned: pinned}
+	return context.WithValue(ctx, callStateKey{}, state), state
+}
+
+// callStateFrom returns the state of the call ctx belongs to. Interceptors
+// that replace the context entirely lose it, and then get fresh state.
+func callStateFrom(ctx context.Context) *callState {
+	if state, ok := ctx.Value(callStateKey{}).(*callState); ok {
+		return state
+	}
+	_, state := withCallState(ctx, nil)
+	return state
+}
+
+// pick returns the endpoint for the next attempt of the call
+func (s *callState) pick(pool *endpointPool) *endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pinned != nil {
+		return s.pinned
+	}
+	node := pool.pick(s.tried)
+	s.tried[node] = true
+	return node
+}
+
+func (s *callState) setRequestID(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requestID = id
+}
+
+// lastRequestID returns the ID of the last request sent, or zero
+func (s *callState) lastRequestID() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requestID
+}
+
+// invoke makes one attempt of a call at the end of the chain
+func (c *RPCClient) invoke(ctx context.Context, method string, params interface{}, result interface{}) error {
+	state := callStateFrom(ctx)
+	node := state.pick(c.pool)
+
+	if batch, ok := params.([]BatchElem); ok && method == BatchMethod {
+		if err := node.breaker.allow(time.Now()); err != nil {
+			return &RPCError{Method: method, Attempts: 1, Err: err}
+		}
+		return c.batchAttempt(ctx, node, batch)
+	}
+
+	if err := node.breaker.allow(time.Now()); err != nil {
+		return &RPCError{Method: method, Attempts: 1, Err: err}
+	}
+
+	id := c.newID()
+	state.setRequestID(id)
+	c.onRequest(ctx, method, id)
+
+	start := time.Now()
+	err := c.call(ctx, node.url(), id, method, params, result)
+	c.pool.report(node, time.Since(start), err)
+	if err != nil {
+		err = &RPCError{Method: method, RequestID: id, Attempts: 1, Err: err}
+	}
+
+	c.onResponse(ctx, method, id, time.Since(start), err)
+	return err
+}
+
+// LoggingInterceptor logs every call to logger, or to slog.Default if nil:
+// successful calls at debug level and failed calls at warn level, with the
+// method, duration, request ID and attempts
+func LoggingInterceptor(logger *slog.Logger) Interceptor {
+	return func(ctx context.Context, method string, params interface{}, result interface{}, next Invoker) error {
+		log := logger
+		if log == nil {
+			log = slog.Default()
+		}
+
+		start := time.Now()
+		err := next(ctx, method, params, result)
+
+		attrs := []any{
+			slog.String("method", method),
+			slog.Duration("duration", time.Since(start)),
+		}
+
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			attrs = append(attrs, slog.Uint64("request_id", rpcErr.RequestID), slog.Int("attempts", rpcErr.Attempts))
+		} else if id := callStateFrom(ctx).lastRequestID(); id != 0 {
+			attrs = append(attrs, slog.Uint64("request_id", id))
+		}
+
+		if err != nil {
+			log.WarnContext(ctx, "rpc call failed", append(attrs, slog.Any("error", err))...)
+		} else {
+			log.DebugContext(ctx, "rpc call", attrs...)
+		}
+		return err
+	}
+}
+
+// MethodStats are the call statistics of one method
+type MethodStats struct {
+	Calls         uint64
+	Errors        uint64
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+}
+
+// CallMetrics counts calls, errors and durations per method. Add it to a
+// client with Use(metrics.Interceptor()).
+type CallMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// NewCallMetrics creates an empty set of call metrics
+func NewCallMetrics() *CallMetrics {
+	return &CallMetrics{methods: make(map[string]*MethodStats)}
+}
+
+// Interceptor returns the interceptor that records calls into m
+func (m *CallMetrics) Interceptor() Interceptor {
+	return MetricsInterceptor(m.observe)
+}
+
+func (m *CallMetrics) observe(ctx context.Context, method string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &MethodStats{}
+		m.methods[method] = stats
+	}
+
+	stats.Calls++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+}
+
+// Methods returns the names of the methods called so far, sorted
+func (m *CallMetrics) Methods() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	methods := make([]string, 0, len(m.methods))
+	for method := range m.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Stats returns the statistics of method
+func (m *CallMetrics) Stats(method string) MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if stats, ok := m.methods[method]; ok {
+		return *stats
+	}
+	return MethodStats{}
+}
+
+// MetricsInterceptor calls observe with the method, duration and error of
+// every call, for example to feed a metrics library
+func MetricsInterceptor(observe func(ctx context.Context, method string, duration time.Duration, err error)) Interceptor {
+	return func(ctx context.Context, method string, params interface{}, result interface{}, next Invoker) error {
+		start := time.Now()
+		err := next(ctx, method, params, result)
+		observe(ctx, method, time.Since(start), err)
+		return err
+	}
+}
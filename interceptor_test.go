@@ -0,0 +1,246 @@
+package chert
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptorOrder(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+			mu.Lock()
+			order = append(order, name+" before")
+			mu.Unlock()
+
+			err := next(ctx, method, params, result)
+
+			mu.Lock()
+			order = append(order, name+" after")
+			mu.Unlock()
+			return err
+		}
+	}
+
+	var attempts int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			http.Error(w, "upstream failed", http.StatusBadGateway)
+			return
+		}
+		req.reply(w, 42)
+	})
+	client := newTestClient(t, server, &ClientConfig{
+		Interceptors: []Interceptor{record("outer"), record("inner")},
+	})
+
+	var height uint64
+	require.NoError(t, client.rpcClient.Call(context.Background(), "getBlockHeight", nil, &height))
+	assert.EqualValues(t, 42, height)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&attempts))
+
+	// The retry happens inside the chain, so each interceptor runs once
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	var calls int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		atomic.AddInt32(&calls, 1)
+		req.reply(w, 1)
+	})
+
+	var inner int32
+	client := newTestClient(t, server, &ClientConfig{
+		Interceptors: []Interceptor{
+			func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+				if method == "getBlockHeight" {
+					*result.(*uint64) = 99
+					return nil
+				}
+				return next(ctx, method, params, result)
+			},
+			func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+				atomic.AddInt32(&inner, 1)
+				return next(ctx, method, params, result)
+			},
+		},
+	})
+
+	var height uint64
+	require.NoError(t, client.rpcClient.Call(context.Background(), "getBlockHeight", nil, &height))
+	assert.EqualValues(t, 99, height)
+	assert.Zero(t, atomic.LoadInt32(&calls), "nothing is sent")
+	assert.Zero(t, atomic.LoadInt32(&inner), "later interceptors are skipped")
+
+	require.NoError(t, client.rpcClient.Call(context.Background(), "getNonce", nil, &height))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestInterceptorMutatesRequest(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		method string
+		param  string
+		header string
+	)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+		method = req.Method
+		req.param(t, &param)
+		header = req.HTTP.Header.Get("X-Tenant")
+		req.reply(w, "chert1")
+	})
+	client := newTestClient(t, server, &ClientConfig{
+		Interceptors: []Interceptor{
+			func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+				ctx = WithRequestHeader(ctx, "X-Tenant", "acme")
+				return next(ctx, "v2_"+method, []string{"rewritten"}, result)
+			},
+		},
+	})
+
+	var result string
+	require.NoError(t, client.rpcClient.Call(context.Background(), "getAddress", []string{"original"}, &result))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "v2_getAddress", method)
+	assert.Equal(t, "rewritten", param)
+	assert.Equal(t, "acme", header)
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		if req.Method == "getBlockHeight" {
+			req.reply(w, 42)
+			return
+		}
+		req.fail(w, -32601, "method not found")
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := newTestClient(t, server, &ClientConfig{
+		Interceptors: []Interceptor{LoggingInterceptor(logger)},
+	})
+
+	var height uint64
+	require.NoError(t, client.rpcClient.Call(context.Background(), "getBlockHeight", nil, &height))
+	assert.Error(t, client.rpcClient.Call(context.Background(), "getMissing", nil, nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	assert.Contains(t, lines[0], "level=DEBUG")
+	assert.Contains(t, lines[0], `msg="rpc call"`)
+	assert.Contains(t, lines[0], "method=getBlockHeight")
+	assert.Contains(t, lines[0], "request_id=1")
+	assert.Contains(t, lines[0], "duration=")
+
+	assert.Contains(t, lines[1], "level=WARN")
+	assert.Contains(t, lines[1], `msg="rpc call failed"`)
+	assert.Contains(t, lines[1], "method=getMissing")
+	assert.Contains(t, lines[1], "request_id=2")
+	assert.Contains(t, lines[1], "attempts=1")
+	assert.Contains(t, lines[1], "method not found")
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		if req.Method == "getBlockHeight" {
+			time.Sleep(2 * time.Millisecond)
+			req.reply(w, 42)
+			return
+		}
+		req.fail(w, -32601, "method not found")
+	})
+
+	metrics := NewCallMetrics()
+	var observed []string
+	client := newTestClient(t, server, &ClientConfig{
+		Interceptors: []Interceptor{
+			metrics.Interceptor(),
+			MetricsInterceptor(func(ctx context.Context, method string, duration time.Duration, err error) {
+				observed = append(observed, method)
+			}),
+		},
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.rpcClient.Call(ctx, "getBlockHeight", nil, nil))
+	require.NoError(t, client.rpcClient.Call(ctx, "getBlockHeight", nil, nil))
+	assert.Error(t, client.rpcClient.Call(ctx, "getMissing", nil, nil))
+
+	assert.Equal(t, []string{"getBlockHeight", "getMissing"}, metrics.Methods())
+	assert.Equal(t, []string{"getBlockHeight", "getBlockHeight", "getMissing"}, observed)
+
+	stats := metrics.Stats("getBlockHeight")
+	assert.EqualValues(t, 2, stats.Calls)
+	assert.Zero(t, stats.Errors)
+	assert.GreaterOrEqual(t, stats.MaxDuration, 2*time.Millisecond)
+	assert.GreaterOrEqual(t, stats.TotalDuration, 4*time.Millisecond)
+
+	stats = metrics.Stats("getMissing")
+	assert.EqualValues(t, 1, stats.Calls)
+	assert.EqualValues(t, 1, stats.Errors)
+
+	assert.Equal(t, MethodStats{}, metrics.Stats("getNonce"))
+}
+
+func TestHealthChecksUseInterceptors(t *testing.T) {
+	var unauthorized int32
+	newServer := func() string {
+		return newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+			if req.HTTP.Header.Get("Authorization") != "Bearer secret" {
+				atomic.AddInt32(&unauthorized, 1)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			req.reply(w, map[string]interface{}{"block_height": 100})
+		}).URL
+	}
+
+	metrics := NewCallMetrics()
+	client := newTestClient(t, newTestRPCServer(t, nil), &ClientConfig{
+		Endpoints: []string{newServer(), newServer()},
+		Pool:      &PoolOptions{HealthCheckInterval: 10 * time.Millisecond},
+		Interceptors: []Interceptor{
+			metrics.Interceptor(),
+			func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+				return next(WithRequestHeader(ctx, "Authorization", "Bearer secret"), method, params, result)
+			},
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		for _, status := range client.Endpoints() {
+			if status.LastChecked.IsZero() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+
+	for _, status := range client.Endpoints() {
+		assert.True(t, status.Healthy, status.URL)
+		assert.NoError(t, status.LastError, status.URL)
+		assert.EqualValues(t, 100, status.BlockHeight, status.URL)
+	}
+	assert.Zero(t, atomic.LoadInt32(&unauthorized))
+	assert.GreaterOrEqual(t, metrics.Stats("getNetworkStatus").Calls, uint64(2))
+}
@@ -0,0 +1,262 @@
+package chert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrAccountNotFound is returned when a keyring or wallet store has no
+// account with the requested address or label
+var ErrAccountNotFound = fmt.Errorf("account %w", ErrNotFound)
+
+// KeyringEntry is an account as persisted by a Keyring. Private keys are
+// only ever stored inside the encrypted Keystore; watch-only entries have none.
+type KeyringEntry struct {
+	Address   string    `json:"address"`
+	PublicKey string    `json:"public_key"`
+	Label     string    `json:"label,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	WatchOnly bool      `json:"watch_only"`
+	Keystore  *Keystore `json:"keystore,omitempty"`
+}
+
+// Keyring is a storage backend for wallet accounts. Implementations must be
+// safe for concurrent use.
+type Keyring interface {
+	// Put creates or replaces the entry for entry.Address
+	Put(entry *KeyringEntry) error
+
+	// Get returns the entry for address, or ErrAccountNotFound
+	Get(address string) (*KeyringEntry, error)
+
+	// List returns all entries
+	List() ([]*KeyringEntry, error)
+
+	// Delete removes the entry for address, or returns ErrAccountNotFound
+	Delete(address string) error
+}
+
+// MemoryKeyring is a Keyring that keeps entries in memory only
+type MemoryKeyring struct {
+	mu      sync.RWMutex
+	entries map[string]*KeyringEntry
+}
+
+// NewMemoryKeyring creates an empty in-memory keyring
+func NewMemoryKeyring() *MemoryKeyring {
+	return &MemoryKeyring{entries: make(map[string]*KeyringEntry)}
+}
+
+// Put stores a copy of entry
+func (k *MemoryKeyring) Put(entry *KeyringEntry) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	stored := *entry
+	k.entries[entry.Address] = &stored
+	return nil
+}
+
+// Get returns a copy of the entry for address
+func (k *MemoryKeyring) Get(address string) (*KeyringEntry, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	entry, ok := k.entries[address]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+
+	stored := *entry
+	return &stored, nil
+}
+
+// List returns copies of all entries
+func (k *MemoryKeyring) List() ([]*KeyringEntry, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	entries := make([]*KeyringEntry, 0, len(k.entries))
+	for _, entry := range k.entries {
+		stored := *entry
+		entries = append(entries, &stored)
+	}
+	return entries, nil
+}
+
+// Delete removes the entry for address
+func (k *MemoryKeyring) Delete(address string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.entries[address]; !ok {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+
+	delete(k.entries, address)
+	return nil
+}
+
+// DirectoryKeyring is a Keyring that stores one JSON file per account in a
+// directory. Files are written atomically with owner-only permissions.
+type DirectoryKeyring struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var keyringFileName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// NewDirectoryKeyring opens a keyring in dir, creating the directory if needed
+func NewDirectoryKeyring(dir string) (*DirectoryKeyring, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create keyring directory: %w", err)
+	}
+
+	return &DirectoryKeyring{dir: dir}, nil
+}
+
+// Put writes the entry to <address>.json
+func (k *DirectoryKeyring) Put(entry *KeyringEntry) error {
+	path, err := k.path(entry.Address)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode keyring entry: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	tmp, err := os.CreateTemp(k.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write keyring entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keyring entry: %w", err)
+	}
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keyring entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write keyring entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write keyring entry: %w", err)
+	}
+
+	return nil
+}
+
+// Get reads the entry for address
+func (k *DirectoryKeyring) Get(address string) (*KeyringEntry, error) {
+	path, err := k.path(address)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return readKeyringEntry(path, address)
+}
+
+// List reads every entry in the directory. Files that cannot be read, or
+// that do not hold the entry for the address they are named after, are
+// skipped so that one damaged or unrelated file does not hide the other
+// accounts; Get still reports why such a file is unusable.
+func (k *DirectoryKeyring) List() ([]*KeyringEntry, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	files, err := os.ReadDir(k.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keyring directory: %w", err)
+	}
+
+	var entries []*KeyringEntry
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		address := strings.TrimSuffix(name, ".json")
+		if !keyringFileName.MatchString(address) {
+			continue
+		}
+
+		entry, err := readKeyringEntry(filepath.Join(k.dir, name), address)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Delete removes the file for address
+func (k *DirectoryKeyring) Delete(address string) error {
+	path, err := k.path(address)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+		}
+		return fmt.Errorf("failed to delete keyring entry: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file for address, rejecting names that could escape dir
+func (k *DirectoryKeyring) path(address string) (string, error) {
+	if !keyringFileName.MatchString(address) {
+		return "", fmt.Errorf("invalid address for keyring: %q", address)
+	}
+	return filepath.Join(k.dir, address+".json"), nil
+}
+
+func readKeyringEntry(path, address string) (*KeyringEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+		}
+		return nil, fmt.Errorf("failed to read keyring entry: %w", err)
+	}
+
+	var entry KeyringEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid keyring entry %s: %w", filepath.Base(path), err)
+	}
+
+	if entry.Address != address {
+		return nil, fmt.Errorf("keyring entry %s holds address %s", filepath.Base(path), entry.Address)
+	}
+
+	return &entry, nil
+}
@@ -0,0 +1,120 @@
+package chert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyrings(t *testing.T) {
+	newKeyrings := map[string]func(t *testing.T) Keyring{
+		"memory": func(t *testing.T) Keyring { return NewMemoryKeyring() },
+		"directory": func(t *testing.T) Keyring {
+			keyring, err := NewDirectoryKeyring(filepath.Join(t.TempDir(), "wallet"))
+			require.NoError(t, err)
+			return keyring
+		},
+	}
+
+	for name, newKeyring := range newKeyrings {
+		t.Run(name, func(t *testing.T) {
+			keyring := newKeyring(t)
+			_, ks := newTestKeystore(t, newTestWallet(t), "correct horse")
+
+			entry := &KeyringEntry{
+				Address:   ks.Address,
+				PublicKey: ks.PublicKey,
+				Label:     "savings",
+				CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+				Keystore:  ks,
+			}
+			watchOnly := &KeyringEntry{
+				Address:   testMainnetAddress,
+				CreatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+				WatchOnly: true,
+			}
+			require.NoError(t, keyring.Put(entry))
+			require.NoError(t, keyring.Put(watchOnly))
+
+			got, err := keyring.Get(entry.Address)
+			require.NoError(t, err)
+			assert.Equal(t, entry, got)
+
+			entries, err := keyring.List()
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []*KeyringEntry{entry, watchOnly}, entries)
+
+			// Put replaces
+			entry.Label = "spending"
+			require.NoError(t, keyring.Put(entry))
+			got, err = keyring.Get(entry.Address)
+			require.NoError(t, err)
+			assert.Equal(t, "spending", got.Label)
+
+			require.NoError(t, keyring.Delete(entry.Address))
+			_, err = keyring.Get(entry.Address)
+			assert.ErrorIs(t, err, ErrAccountNotFound)
+			assert.ErrorIs(t, keyring.Delete(entry.Address), ErrAccountNotFound)
+
+			entries, err = keyring.List()
+			require.NoError(t, err)
+			assert.Equal(t, []*KeyringEntry{watchOnly}, entries)
+		})
+	}
+}
+
+func TestDirectoryKeyringFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyring, err := NewDirectoryKeyring(dir)
+	require.NoError(t, err)
+
+	entry := &KeyringEntry{Address: testMainnetAddress, WatchOnly: true}
+	require.NoError(t, keyring.Put(entry))
+
+	info, err := os.Stat(filepath.Join(dir, testMainnetAddress+".json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Files other than valid entries named after their address are skipped
+	files := map[string]string{
+		"settings.json":                  `{"theme": "dark"}`,
+		"backup-2026.json":               `{"address": "chert1abc"}`,
+		"chert1corrupt.json":             `{"address": `,
+		testValidatorAddress + ".json":   `{"address": "` + testMainnetAddress + `"}`,
+		"notes.txt":                      "not json",
+		"chert1directory.json/README.md": "not a file",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	entries, err := keyring.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, testMainnetAddress, entries[0].Address)
+
+	// Get explains what is wrong with a damaged entry
+	_, err = keyring.Get("chert1corrupt")
+	assert.ErrorContains(t, err, "invalid keyring entry")
+	_, err = keyring.Get(testValidatorAddress)
+	assert.ErrorContains(t, err, "holds address "+testMainnetAddress)
+
+	for _, address := range []string{"", "../escape", "a/b", "chert1.json"} {
+		assert.Error(t, keyring.Put(&KeyringEntry{Address: address}), address)
+		_, err := keyring.Get(address)
+		assert.Error(t, err, address)
+	}
+
+	// The keyring reopens with the same entries
+	reopened, err := NewDirectoryKeyring(dir)
+	require.NoError(t, err)
+	got, err := reopened.Get(testMainnetAddress)
+	require.NoError(t, err)
+	assert.Equal(t, entry, got)
+}
@@ -0,0 +1,88 @@
+package chert
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+)
+
+// KeyPairFromSeed derives an Ed25519 keypair from a 32-byte seed.
+// The private key is returned as the hex-encoded seed, which is the
+// format accepted by ImportAccount.
+func KeyPairFromSeed(seed []byte) (string, string, error) {
+	if len(seed) != ed25519.SeedSize {
+		return "", "", fmt.Errorf("invalid seed length: expected %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+
+	key := ed25519.NewKeyFromSeed(seed)
+	publicKey := key.Public().(ed25519.PublicKey)
+
+	return hex.EncodeToString(seed), hex.EncodeToString(publicKey), nil
+}
+
+// DerivePublicKey derives the hex-encoded Ed25519 public key for a private key
+func DerivePublicKey(privateKey string) (string, error) {
+	key, err := decodePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key.Public().(ed25519.PublicKey)), nil
+}
+
+// SignMessage signs a message with a hex-encoded Ed25519 private key and
+// returns the hex-encoded signature
+func SignMessage(privateKey string, message []byte) (string, error) {
+	key, err := decodePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(ed25519.Sign(key, message)), nil
+}
+
+// VerifySignature reports whether signature is a valid Ed25519 signature of
+// message by publicKey. An error is returned only for malformed inputs.
+func VerifySignature(publicKey string, message []byte, signature string) (bool, error) {
+	publicKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return false, fmt.Errorf("invalid public key hex: %w", err)
+	}
+
+	if len(publicKeyBytes) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid public key length")
+	}
+
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature hex: %w", err)
+	}
+
+	if len(signatureBytes) != ed25519.SignatureSize {
+		return false, fmt.Errorf("invalid signature length")
+	}
+
+	return ed25519.Verify(publicKeyBytes, message, signatureBytes), nil
+}
+
+// decodePrivateKey accepts either a 32-byte seed or a 64-byte expanded
+// Ed25519 private key (seed followed by public key) in hex
+func decodePrivateKey(privateKey string) (ed25519.PrivateKey, error) {
+	privateKeyBytes, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key hex: %w", err)
+	}
+
+	switch len(privateKeyBytes) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(privateKeyBytes), nil
+	case ed25519.PrivateKeySize:
+		key := ed25519.NewKeyFromSeed(privateKeyBytes[:ed25519.SeedSize])
+		if !key.Equal(ed25519.PrivateKey(privateKeyBytes)) {
+			return nil, fmt.Errorf("private key does not match its embedded public key")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("invalid private key length")
+	}
+}
@@ -0,0 +1,353 @@
+package chert
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeystoreVersion is the version of the keystore format written by ExportKeystore
+const KeystoreVersion = 1
+
+const (
+	keystoreCipher = "aes-256-gcm"
+	keystoreKDF    = "scrypt"
+	keystoreKeyLen = 64
+)
+
+var (
+	// ErrInvalidPassword is returned when a keystore cannot be unlocked with
+	// the given password
+	ErrInvalidPassword = errors.New("invalid keystore password")
+
+	// ErrKeystoreCorrupted is returned when a keystore file is malformed or
+	// its contents fail integrity checks
+	ErrKeystoreCorrupted = errors.New("keystore corrupted")
+
+	// ErrUnsupportedKeystore is returned for keystores with an unknown
+	// version, cipher or key derivation function, or with scrypt parameters
+	// outside the supported range
+	ErrUnsupportedKeystore = errors.New("unsupported keystore")
+)
+
+// ScryptParams holds the scrypt cost parameters used to derive keystore keys
+type ScryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+var (
+	// StandardScryptParams is the default, suitable for files at rest
+	StandardScryptParams = ScryptParams{N: 1 << 18, R: 8, P: 1}
+
+	// LightScryptParams trades strength for speed on constrained devices
+	LightScryptParams = ScryptParams{N: 1 << 12, R: 8, P: 6}
+)
+
+// Limits on the scrypt parameters accepted from keystore files, so that a
+// damaged or hostile file cannot make decryption panic or exhaust memory
+const (
+	maxScryptN      = 1 << 20
+	maxScryptR      = 32
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30
+)
+
+// validate checks that the parameters are within the supported limits
+func (p ScryptParams) validate() error {
+	if p.N < 2 || p.N > maxScryptN || p.N&(p.N-1) != 0 {
+		return fmt.Errorf("scrypt n %d must be a power of two between 2 and %d", p.N, maxScryptN)
+	}
+
+	if p.R < 1 || p.R > maxScryptR {
+		return fmt.Errorf("scrypt r %d must be between 1 and %d", p.R, maxScryptR)
+	}
+
+	if p.P < 1 || p.P > maxScryptP {
+		return fmt.Errorf("scrypt p %d must be between 1 and %d", p.P, maxScryptP)
+	}
+
+	// scrypt needs 128*N*r bytes of memory
+	if 128*p.N*p.R > maxScryptMemory {
+		return fmt.Errorf("scrypt n %d and r %d need more than %d bytes of memory", p.N, p.R, maxScryptMemory)
+	}
+
+	return nil
+}
+
+// Keystore is a password-encrypted account.
+//
+// The password is stretched with scrypt into a 64-byte key. The first half
+// encrypts the private key with AES-256-GCM, authenticating the address and
+// public key as additional data. The second half keys an HMAC-SHA256 over the
+// address and public key, stored as MAC, which tells a wrong password apart
+// from a damaged ciphertext.
+type Keystore struct {
+	Version   int            `json:"version"`
+	ID        string         `json:"id"`
+	Address   string         `json:"address"`
+	PublicKey string         `json:"public_key"`
+	Crypto    KeystoreCrypto `json:"crypto"`
+}
+
+// KeystoreCrypto holds the encrypted key and the parameters to decrypt it
+type KeystoreCrypto struct {
+	Cipher     string          `json:"cipher"`
+	CipherText string          `json:"ciphertext"`
+	Nonce      string          `json:"nonce"`
+	KDF        string          `json:"kdf"`
+	KDFParams  KeystoreKDFArgs `json:"kdfparams"`
+	MAC        string          `json:"mac"`
+}
+
+// KeystoreKDFArgs holds the scrypt parameters and salt of a keystore
+type KeystoreKDFArgs struct {
+	ScryptParams
+	Salt string `json:"salt"`
+}
+
+// EncryptKeystore encrypts an account's private key with a password
+func EncryptKeystore(account *Account, password string, params ScryptParams) (*Keystore, error) {
+	if account == nil || account.PrivateKey == "" {
+		return nil, fmt.Errorf("account does not have a private key")
+	}
+
+	if password == "" {
+		return nil, fmt.Errorf("keystore password is required")
+	}
+
+	privateKey, err := decodePrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := DerivePublicKey(account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.PublicKey != "" && account.PublicKey != publicKey {
+		return nil, fmt.Errorf("account public key does not match its private key")
+	}
+
+	if !addressMatches(account.Address, publicKey) {
+		return nil, fmt.Errorf("account address %q does not match its key", account.Address)
+	}
+
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scrypt parameters: %w", err)
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	derived, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, keystoreKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+
+	aead, err := newKeystoreAEAD(derived[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ks := &Keystore{
+		Version:   KeystoreVersion,
+		ID:        uuid.New().String(),
+		Address:   account.Address,
+		PublicKey: publicKey,
+	}
+
+	ciphertext := aead.Seal(nil, nonce, privateKey.Seed(), ks.authenticatedData())
+
+	ks.Crypto = KeystoreCrypto{
+		Cipher:     keystoreCipher,
+		CipherText: hex.EncodeToString(ciphertext),
+		Nonce:      hex.EncodeToString(nonce),
+		KDF:        keystoreKDF,
+		KDFParams: KeystoreKDFArgs{
+			ScryptParams: params,
+			Salt:         hex.EncodeToString(salt),
+		},
+		MAC: hex.EncodeToString(ks.mac(derived[32:])),
+	}
+
+	return ks, nil
+}
+
+// ParseKeystore decodes a keystore file without decrypting it
+func ParseKeystore(data []byte) (*Keystore, error) {
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeystoreCorrupted, err)
+	}
+
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedKeystore, ks.Version)
+	}
+
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("%w: cipher %q", ErrUnsupportedKeystore, ks.Crypto.Cipher)
+	}
+
+	if ks.Crypto.KDF != keystoreKDF {
+		return nil, fmt.Errorf("%w: kdf %q", ErrUnsupportedKeystore, ks.Crypto.KDF)
+	}
+
+	if err := ks.Crypto.KDFParams.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeystore, err)
+	}
+
+	return &ks, nil
+}
+
+// Decrypt unlocks the keystore with a password and returns the account
+func (ks *Keystore) Decrypt(password string) (*Account, error) {
+	// Keystores need not come from ParseKeystore
+	params := ks.Crypto.KDFParams.ScryptParams
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeystore, err)
+	}
+
+	salt, err := hex.DecodeString(ks.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid salt", ErrKeystoreCorrupted)
+	}
+
+	nonce, err := hex.DecodeString(ks.Crypto.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrKeystoreCorrupted)
+	}
+
+	ciphertext, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ciphertext", ErrKeystoreCorrupted)
+	}
+
+	expectedMAC, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid mac", ErrKeystoreCorrupted)
+	}
+
+	derived, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, keystoreKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid scrypt parameters: %v", ErrKeystoreCorrupted, err)
+	}
+
+	if !hmac.Equal(ks.mac(derived[32:]), expectedMAC) {
+		return nil, ErrInvalidPassword
+	}
+
+	aead, err := newKeystoreAEAD(derived[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce length", ErrKeystoreCorrupted)
+	}
+
+	seed, err := aead.Open(nil, nonce, ciphertext, ks.authenticatedData())
+	if err != nil {
+		return nil, fmt.Errorf("%w: ciphertext failed authentication", ErrKeystoreCorrupted)
+	}
+
+	privateKey, publicKey, err := KeyPairFromSeed(seed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeystoreCorrupted, err)
+	}
+
+	if publicKey != ks.PublicKey {
+		return nil, fmt.Errorf("%w: decrypted key does not match public key", ErrKeystoreCorrupted)
+	}
+
+	return &Account{
+		Address:    ks.Address,
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+	}, nil
+}
+
+// authenticatedData binds the plaintext header fields to the ciphertext
+func (ks *Keystore) authenticatedData() []byte {
+	var enc txEncoder
+	enc.string(ks.Address)
+	enc.string(ks.PublicKey)
+	return enc.buf
+}
+
+// mac computes the password check over the plaintext header fields
+func (ks *Keystore) mac(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(ks.authenticatedData())
+	return mac.Sum(nil)
+}
+
+func newKeystoreAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// ExportKeystore encrypts an account with a password and returns the
+// keystore file contents
+func (wm *WalletManager) ExportKeystore(account *Account, password string) ([]byte, error) {
+	ks, err := EncryptKeystore(account, password, StandardScryptParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// ImportKeystore decrypts keystore file contents with a password. It
+// returns ErrInvalidPassword, ErrKeystoreCorrupted or ErrUnsupportedKeystore
+// (wrapped) when the keystore cannot be opened.
+func (wm *WalletManager) ImportKeystore(data []byte, password string) (*Account, error) {
+	ks, err := ParseKeystore(data)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := ks.Decrypt(password)
+	if err != nil {
+		return nil, err
+	}
+
+	if !addressMatches(account.Address, account.PublicKey) {
+		return nil, fmt.Errorf("%w: address does not match public key", ErrKeystoreCorrupted)
+	}
+
+	return account, nil
+}
+
+// addressMatches reports whether address is the address of publicKey on the
+// network address belongs to
+func addressMatches(address, publicKey string) bool {
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return false
+	}
+
+	generated, err := GenerateAddress(publicKey, parsed.Network)
+	return err == nil && generated == address
+}
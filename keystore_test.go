@@ -0,0 +1,178 @@
+package chert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testScryptParams keep the tests fast; they are far too weak for real use
+var testScryptParams = ScryptParams{N: 1 << 4, R: 1, P: 1}
+
+func newTestKeystore(t *testing.T, wallet *WalletManager, password string) (*Account, *Keystore) {
+	t.Helper()
+
+	account, err := wallet.CreateAccount()
+	require.NoError(t, err)
+
+	ks, err := EncryptKeystore(account, password, testScryptParams)
+	require.NoError(t, err)
+	return account, ks
+}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	wallet := newTestWallet(t)
+	account, ks := newTestKeystore(t, wallet, "correct horse")
+
+	data, err := json.Marshal(ks)
+	require.NoError(t, err)
+
+	imported, err := wallet.ImportKeystore(data, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, account, imported)
+}
+
+func TestKeystoreErrors(t *testing.T) {
+	wallet := newTestWallet(t)
+	_, ks := newTestKeystore(t, wallet, "correct horse")
+
+	tests := []struct {
+		name     string
+		password string
+		modify   func(ks *Keystore)
+		want     error
+	}{
+		{
+			name:     "wrong password",
+			password: "battery staple",
+			want:     ErrInvalidPassword,
+		},
+		{
+			name:   "tampered ciphertext",
+			modify: func(ks *Keystore) { ks.Crypto.CipherText = flipHexDigit(ks.Crypto.CipherText) },
+			want:   ErrKeystoreCorrupted,
+		},
+		{
+			name:   "truncated nonce",
+			modify: func(ks *Keystore) { ks.Crypto.Nonce = ks.Crypto.Nonce[2:] },
+			want:   ErrKeystoreCorrupted,
+		},
+		{
+			name:   "invalid salt",
+			modify: func(ks *Keystore) { ks.Crypto.KDFParams.Salt = "zz" },
+			want:   ErrKeystoreCorrupted,
+		},
+		{
+			name:   "unknown version",
+			modify: func(ks *Keystore) { ks.Version = 2 },
+			want:   ErrUnsupportedKeystore,
+		},
+		{
+			name:   "unknown cipher",
+			modify: func(ks *Keystore) { ks.Crypto.Cipher = "aes-128-ctr" },
+			want:   ErrUnsupportedKeystore,
+		},
+		{
+			name:   "zero scrypt p",
+			modify: func(ks *Keystore) { ks.Crypto.KDFParams.P = 0 },
+			want:   ErrUnsupportedKeystore,
+		},
+		{
+			name:   "scrypt n not a power of two",
+			modify: func(ks *Keystore) { ks.Crypto.KDFParams.N = 1000 },
+			want:   ErrUnsupportedKeystore,
+		},
+		{
+			name:   "scrypt n too large",
+			modify: func(ks *Keystore) { ks.Crypto.KDFParams.N = 1 << 30 },
+			want:   ErrUnsupportedKeystore,
+		},
+		{
+			name:   "scrypt memory too large",
+			modify: func(ks *Keystore) { ks.Crypto.KDFParams.N, ks.Crypto.KDFParams.R = 1<<20, 32 },
+			want:   ErrUnsupportedKeystore,
+		},
+		{
+			name:   "negative scrypt r",
+			modify: func(ks *Keystore) { ks.Crypto.KDFParams.R = -1 },
+			want:   ErrUnsupportedKeystore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := *ks
+			if tt.modify != nil {
+				tt.modify(&modified)
+			}
+
+			password := tt.password
+			if password == "" {
+				password = "correct horse"
+			}
+
+			data, err := json.Marshal(&modified)
+			require.NoError(t, err)
+
+			_, err = wallet.ImportKeystore(data, password)
+			assert.ErrorIs(t, err, tt.want)
+
+			// Decrypt checks the parameters without ParseKeystore too
+			if tt.want == ErrUnsupportedKeystore && modified.Version == KeystoreVersion && modified.Crypto.Cipher == keystoreCipher {
+				_, err = modified.Decrypt(password)
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeystoreMalformedJSON(t *testing.T) {
+	_, err := ParseKeystore([]byte(`{"version": 1,`))
+	assert.ErrorIs(t, err, ErrKeystoreCorrupted)
+}
+
+func TestEncryptKeystoreChecksAccount(t *testing.T) {
+	wallet := newTestWallet(t)
+
+	account, err := wallet.CreateAccount()
+	require.NoError(t, err)
+	other, err := wallet.CreateAccount()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(a *Account)
+		params ScryptParams
+	}{
+		{"address of another key", func(a *Account) { a.Address = other.Address }, testScryptParams},
+		{"missing address", func(a *Account) { a.Address = "" }, testScryptParams},
+		{"public key of another key", func(a *Account) { a.PublicKey = other.PublicKey }, testScryptParams},
+		{"zero scrypt p", func(a *Account) {}, ScryptParams{N: 1 << 4, R: 1}},
+		{"scrypt n too large", func(a *Account) {}, ScryptParams{N: 1 << 21, R: 1, P: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := *account
+			tt.modify(&modified)
+
+			_, err := EncryptKeystore(&modified, "correct horse", tt.params)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScryptParamsDefaultsAreValid(t *testing.T) {
+	assert.NoError(t, StandardScryptParams.validate())
+	assert.NoError(t, LightScryptParams.validate())
+}
+
+// flipHexDigit changes the first digit of a hex string
+func flipHexDigit(s string) string {
+	if s[0] == '0' {
+		return "1" + s[1:]
+	}
+	return "0" + s[1:]
+}
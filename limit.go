@@ -0,0 +1,163 @@
+package chert
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ThrottleStats reports how much the client's rate limit and concurrency cap
+// have held requests back
+type ThrottleStats struct {
+	// Requests is the number of HTTP requests sent
+	Requests uint64
+
+	// Throttled is the number of requests that had to wait
+	Throttled uint64
+
+	// TotalWait is the time requests spent waiting, summed
+	TotalWait time.Duration
+
+	// InFlight is the number of requests being sent right now
+	InFlight int64
+}
+
+// throttle applies a token-bucket rate limit and a cap on concurrent
+// requests. The zero value does neither.
+type throttle struct {
+	limiter  *rateLimiter
+	inFlight chan struct{}
+
+	requests  uint64
+	throttled uint64
+	waitNanos int64
+	active    int64
+}
+
+// newThrottle creates a throttle allowing rate requests per second with
+// bursts of up to burst, and at most maxInFlight requests at once. Zero
+// disables the corresponding limit.
+func newThrottle(rate float64, burst, maxInFlight int) *throttle {
+	t := &throttle{}
+	if rate > 0 {
+		if burst <= 0 {
+			burst = int(math.Max(1, math.Ceil(rate)))
+		}
+		t.limiter = &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	}
+	if maxInFlight > 0 {
+		t.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return t
+}
+
+// acquire waits until a request may be sent and returns how long it had to
+// wait, or zero if it did not. release must be called once the request
+// completes, unless an error is returned.
+func (t *throttle) acquire(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	blocked := false
+
+	if t.limiter != nil {
+		delay := t.limiter.reserve(start)
+		if delay > 0 {
+			blocked = true
+			if err := t.limiter.wait(ctx, delay); err != nil {
+				return time.Since(start), fmt.Errorf("waiting for rate limiter: %w", err)
+			}
+		}
+	}
+
+	if t.inFlight != nil {
+		select {
+		case t.inFlight <- struct{}{}:
+		default:
+			blocked = true
+			select {
+			case t.inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return time.Since(start), fmt.Errorf("waiting for a free request slot: %w", ctx.Err())
+			}
+		}
+	}
+
+	atomic.AddUint64(&t.requests, 1)
+	atomic.AddInt64(&t.active, 1)
+	if !blocked {
+		return 0, nil
+	}
+
+	waited := time.Since(start)
+	atomic.AddUint64(&t.throttled, 1)
+	atomic.AddInt64(&t.waitNanos, int64(waited))
+	return waited, nil
+}
+
+// release frees the slot taken by acquire
+func (t *throttle) release() {
+	atomic.AddInt64(&t.active, -1)
+	if t.inFlight != nil {
+		<-t.inFlight
+	}
+}
+
+func (t *throttle) stats() ThrottleStats {
+	return ThrottleStats{
+		Requests:  atomic.LoadUint64(&t.requests),
+		Throttled: atomic.LoadUint64(&t.throttled),
+		TotalWait: time.Duration(atomic.LoadInt64(&t.waitNanos)),
+		InFlight:  atomic.LoadInt64(&t.active),
+	}
+}
+
+// rateLimiter is a token bucket refilled at rate tokens per second up to
+// burst. Each request takes a token; tokens may go negative, which queues
+// requests behind each other.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve but not used
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// wait blocks for the delay of a reserved token, and gives the token back
+// if ctx is done first
+func (l *rateLimiter) wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
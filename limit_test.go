@@ -0,0 +1,145 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	start := time.Now()
+	limiter := &rateLimiter{rate: 10, burst: 2, tokens: 2, last: start}
+
+	// The burst is free, then requests queue 100ms apart
+	assert.Zero(t, limiter.reserve(start))
+	assert.Zero(t, limiter.reserve(start))
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(start))
+	assert.Equal(t, 200*time.Millisecond, limiter.reserve(start))
+
+	// Tokens refill over time, up to the burst
+	later := start.Add(time.Hour)
+	assert.Zero(t, limiter.reserve(later))
+	assert.Zero(t, limiter.reserve(later))
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(later))
+}
+
+func TestThrottleRateWaitRespectsContext(t *testing.T) {
+	th := newThrottle(1, 1, 0)
+
+	_, err := th.acquire(context.Background())
+	require.NoError(t, err)
+	th.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	waited, err := th.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.GreaterOrEqual(t, waited, 20*time.Millisecond)
+
+	// The abandoned token was given back, so the next request waits about a
+	// second, not two
+	assert.LessOrEqual(t, th.limiter.reserve(time.Now()), time.Second)
+	assert.EqualValues(t, 1, th.stats().Requests)
+}
+
+func TestThrottleSlotWaitRespectsContext(t *testing.T) {
+	th := newThrottle(0, 0, 1)
+
+	_, err := th.acquire(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, th.stats().InFlight)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err = th.acquire(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Releasing the slot lets the next request through
+	th.release()
+	waited, err := th.acquire(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, waited)
+	th.release()
+
+	stats := th.stats()
+	assert.EqualValues(t, 2, stats.Requests)
+	assert.Zero(t, stats.InFlight)
+}
+
+func TestClientMaxInFlight(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		active  int
+		highest int
+	)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		mu.Lock()
+		active++
+		if active > highest {
+			highest = active
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		req.reply(w, 1)
+	})
+
+	var throttled int
+	client := newTestClient(t, server, &ClientConfig{
+		MaxInFlight: 2,
+		Hooks: &RPCHooks{
+			OnThrottle: func(ctx context.Context, method string, wait time.Duration) {
+				mu.Lock()
+				throttled++
+				mu.Unlock()
+			},
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.RPC().Call(context.Background(), "getBlockHeight", nil, nil))
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.LessOrEqual(t, highest, 2)
+	assert.Positive(t, throttled)
+	assert.EqualValues(t, throttled, client.RPC().ThrottleStats().Throttled)
+}
+
+func TestClientRateLimitRespectsContext(t *testing.T) {
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		req.reply(w, 1)
+	})
+	client := newTestClient(t, server, &ClientConfig{RateLimit: 1, RateBurst: 1})
+
+	require.NoError(t, client.RPC().Call(context.Background(), "getBlockHeight", nil, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.RPC().Call(ctx, "getBlockHeight", nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,161 @@
+package chert
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrInvalidMnemonicLength is returned for mnemonics that do not have
+	// 12, 15, 18, 21 or 24 words
+	ErrInvalidMnemonicLength = errors.New("invalid mnemonic length")
+
+	// ErrUnknownMnemonicWord is returned for words outside the BIP39 English wordlist
+	ErrUnknownMnemonicWord = errors.New("unknown mnemonic word")
+
+	// ErrInvalidMnemonicChecksum is returned when the mnemonic checksum does not match
+	ErrInvalidMnemonicChecksum = errors.New("invalid mnemonic checksum")
+)
+
+// bip39WordIndex maps each wordlist entry to its 11-bit index
+var bip39WordIndex = func() map[string]int {
+	index := make(map[string]int, len(bip39English))
+	for i, word := range bip39English {
+		index[word] = i
+	}
+	return index
+}()
+
+// GenerateMnemonic generates a new BIP39 mnemonic of 12 or 24 words
+func (wm *WalletManager) GenerateMnemonic(wordCount int) (string, error) {
+	if wordCount != 12 && wordCount != 24 {
+		return "", fmt.Errorf("%w: word count must be 12 or 24, got %d", ErrInvalidMnemonicLength, wordCount)
+	}
+
+	// Every 3 words carry 32 bits of entropy and 1 bit of checksum
+	entropy := make([]byte, wordCount/3*4)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	return entropyToMnemonic(entropy), nil
+}
+
+// CreateAccountFromMnemonic derives an account from a BIP39 mnemonic and an
+// optional passphrase. The account is the first HD account, at
+// DefaultDerivationPath(0, 0); use NewHDWalletFromMnemonic to derive further
+// accounts. The same mnemonic and passphrase always produce the same account.
+func (wm *WalletManager) CreateAccountFromMnemonic(mnemonic, passphrase string) (*Account, error) {
+	hd, err := wm.NewHDWalletFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return hd.DeriveAccountAt(DefaultDerivationPath(0, 0))
+}
+
+// RecoverAccount restores the account that CreateAccountFromMnemonic derived
+// from a mnemonic and passphrase. Extra whitespace and capitalization in the
+// mnemonic are ignored.
+func (wm *WalletManager) RecoverAccount(mnemonic, passphrase string) (*Account, error) {
+	account, err := wm.CreateAccountFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover account: %w", err)
+	}
+
+	return account, nil
+}
+
+// ValidateMnemonic checks that every word is in the BIP39 English wordlist
+// and that the checksum matches
+func ValidateMnemonic(mnemonic string) error {
+	_, err := mnemonicToEntropy(normalizeMnemonic(mnemonic))
+	return err
+}
+
+// MnemonicToSeed validates a mnemonic and derives the 64-byte BIP39 seed
+// using PBKDF2-HMAC-SHA512 with the optional passphrase
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	words := normalizeMnemonic(mnemonic)
+	if _, err := mnemonicToEntropy(words); err != nil {
+		return nil, err
+	}
+
+	password := norm.NFKD.String(strings.Join(words, " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key([]byte(password), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+// normalizeMnemonic splits a mnemonic into lowercase words
+func normalizeMnemonic(mnemonic string) []string {
+	return strings.Fields(strings.ToLower(norm.NFKD.String(mnemonic)))
+}
+
+// entropyToMnemonic encodes entropy and its SHA-256 checksum as words
+func entropyToMnemonic(entropy []byte) string {
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+
+	wordCount := len(entropy) * 8 * 33 / 32 / 11
+	words := make([]string, wordCount)
+	for i := range words {
+		words[i] = bip39English[readBits(data, i*11, 11)]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// mnemonicToEntropy decodes words back to entropy, verifying the checksum
+func mnemonicToEntropy(words []string) ([]byte, error) {
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonicLength, len(words))
+	}
+
+	data := make([]byte, (len(words)*11+7)/8)
+	for i, word := range words {
+		index, ok := bip39WordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownMnemonicWord, word)
+		}
+		writeBits(data, i*11, 11, index)
+	}
+
+	entropyBits := len(words) * 11 * 32 / 33
+	checksumBits := entropyBits / 32
+	entropy := data[:entropyBits/8]
+
+	checksum := sha256.Sum256(entropy)
+	if readBits(data, entropyBits, checksumBits) != readBits(checksum[:], 0, checksumBits) {
+		return nil, ErrInvalidMnemonicChecksum
+	}
+
+	return entropy, nil
+}
+
+// readBits reads count bits starting at bit offset as a big-endian integer
+func readBits(data []byte, offset, count int) int {
+	value := 0
+	for i := offset; i < offset+count; i++ {
+		bit := (data[i/8] >> (7 - uint(i%8))) & 1
+		value = value<<1 | int(bit)
+	}
+	return value
+}
+
+// writeBits writes the low count bits of value starting at bit offset
+func writeBits(data []byte, offset, count, value int) {
+	for i := 0; i < count; i++ {
+		if value>>(count-1-i)&1 == 1 {
+			pos := offset + i
+			data[pos/8] |= 1 << (7 - uint(pos%8))
+		}
+	}
+}
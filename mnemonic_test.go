@@ -0,0 +1,74 @@
+package chert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		want     error
+	}{
+		{"valid", testMnemonic, nil},
+		{"extra whitespace and capitals", "  Abandon abandon ABANDON abandon abandon abandon\tabandon abandon abandon abandon abandon about \n", nil},
+		{"too short", "abandon abandon abandon", ErrInvalidMnemonicLength},
+		{"not a multiple of three", testMnemonic + " abandon", ErrInvalidMnemonicLength},
+		{"too long", strings.Repeat("abandon ", 27), ErrInvalidMnemonicLength},
+		{"empty", "", ErrInvalidMnemonicLength},
+		{"unknown word", strings.Replace(testMnemonic, "about", "aboot", 1), ErrUnknownMnemonicWord},
+		{"bad checksum", strings.Replace(testMnemonic, "about", "abandon", 1), ErrInvalidMnemonicChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMnemonic(tt.mnemonic)
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	wallet := newTestWallet(t)
+
+	for _, count := range []int{12, 24} {
+		mnemonic, err := wallet.GenerateMnemonic(count)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), count)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := wallet.GenerateMnemonic(15)
+	assert.ErrorIs(t, err, ErrInvalidMnemonicLength)
+}
+
+func TestMnemonicAccountIsFirstHDAccount(t *testing.T) {
+	wallet := newTestWallet(t)
+
+	account, err := wallet.CreateAccountFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+
+	hd, err := wallet.NewHDWalletFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+	first, err := hd.DeriveAccounts(0, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, first[0], account)
+
+	recovered, err := wallet.RecoverAccount(strings.ToUpper(testMnemonic), "")
+	require.NoError(t, err)
+	assert.Equal(t, account, recovered)
+
+	other, err := wallet.RecoverAccount(testMnemonic, "passphrase")
+	require.NoError(t, err)
+	assert.NotEqual(t, account.Address, other.Address)
+}
@@ -0,0 +1,260 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// maxNonceRetries bounds how often a send is retried after "nonce too low"
+const maxNonceRetries = 3
+
+// GetNonce returns the next nonce the network expects from address,
+// including transactions still pending in the mempool
+func (wm *WalletManager) GetNonce(ctx context.Context, address string) (uint64, error) {
+	var result uint64
+	err := wm.client.rpcClient.Call(ctx, "getNonce", []interface{}{address}, &result)
+	return result, err
+}
+
+// NonceManager hands out sequential nonces per account and is safe for
+// concurrent use. The first nonce for an account is fetched with GetNonce;
+// later ones are counted locally, so many transactions from one account can
+// be in flight at once.
+type NonceManager struct {
+	client *ChertClient
+
+	mu       sync.Mutex
+	accounts map[string]*accountNonces
+}
+
+// accountNonces is the nonce state of one account
+type accountNonces struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64
+	released []uint64
+}
+
+// NewNonceManager creates a nonce manager for accounts on client's network
+func NewNonceManager(client *ChertClient) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		accounts: make(map[string]*accountNonces),
+	}
+}
+
+// account returns the state for address, creating it if needed
+func (nm *NonceManager) account(address string) *accountNonces {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	state, ok := nm.accounts[address]
+	if !ok {
+		state = &accountNonces{}
+		nm.accounts[address] = state
+	}
+	return state
+}
+
+// Next reserves the next nonce for address. Nonces given back with Release
+// are handed out again first, lowest first, so gaps are filled before new
+// nonces are used.
+func (nm *NonceManager) Next(ctx context.Context, address string) (uint64, error) {
+	state := nm.account(address)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.synced {
+		nonce, err := nm.client.Wallet.GetNonce(ctx, address)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch nonce for %s: %w", address, err)
+		}
+		state.next = nonce
+		state.synced = true
+	}
+
+	if len(state.released) > 0 {
+		nonce := state.released[0]
+		state.released = state.released[1:]
+		return nonce, nil
+	}
+
+	nonce := state.next
+	state.next++
+	return nonce, nil
+}
+
+// Release gives back a nonce reserved with Next whose transaction was never
+// accepted by the network, so it is reused by the next send
+func (nm *NonceManager) Release(address string, nonce uint64) {
+	state := nm.account(address)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.synced || nonce >= state.next {
+		return
+	}
+
+	for _, released := range state.released {
+		if released == nonce {
+			return
+		}
+	}
+
+	state.released = append(state.released, nonce)
+	sort.Slice(state.released, func(i, j int) bool { return state.released[i] < state.released[j] })
+}
+
+// Resync discards the local state for address and fetches the nonce from
+// the network again. Use it when transactions were dropped from the
+// mempool; nonces of transactions still in flight may be handed out again.
+func (nm *NonceManager) Resync(ctx context.Context, address string) error {
+	state := nm.account(address)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	nonce, err := nm.client.Wallet.GetNonce(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to fetch nonce for %s: %w", address, err)
+	}
+
+	state.next = nonce
+	state.released = nil
+	state.synced = true
+	return nil
+}
+
+// advance moves the next nonce for address forward to the network's nonce
+// and drops released nonces the network has already seen. Unlike Resync it
+// never moves backwards, so nonces of transactions still in flight are not
+// handed out twice.
+func (nm *NonceManager) advance(ctx context.Context, address string) error {
+	nonce, err := nm.client.Wallet.GetNonce(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to fetch nonce for %s: %w", address, err)
+	}
+
+	state := nm.account(address)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.synced || nonce > state.next {
+		state.next = nonce
+		state.synced = true
+	}
+
+	kept := state.released[:0]
+	for _, released := range state.released {
+		if released >= nonce {
+			kept = append(kept, released)
+		}
+	}
+	state.released = kept
+	return nil
+}
+
+// Reset forgets address, so its next nonce is fetched from the network
+func (nm *NonceManager) Reset(address string) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	delete(nm.accounts, address)
+}
+
+// SendTransaction sends a transfer from signer with the next nonce. The
+// request's Nonce is ignored and the request is not modified.
+func (nm *NonceManager) SendTransaction(ctx context.Context, request *TransactionRequest, signer Signer) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
+	}
+
+	unsigned, err := nm.client.Wallet.BuildTransaction(signer.Address(), request)
+	if err != nil {
+		return "", err
+	}
+
+	return nm.SignAndBroadcast(ctx, unsigned, signer)
+}
+
+// SignAndBroadcast sets the next nonce on a transaction built by any of the
+// Build methods, signs it and broadcasts it. On "nonce too low" the nonce is
+// advanced to the network's and the send retried.
+//
+// The nonce is released for reuse only if the transaction was never sent or
+// the node clearly rejected it. If the outcome is unknown, for example after
+// a timeout, the nonce stays reserved and the transaction hash is returned
+// with an error matching ErrBroadcastUnknown; look the transaction up before
+// sending it again, and Release or Resync if it never arrived.
+func (nm *NonceManager) SignAndBroadcast(ctx context.Context, tx *UnsignedTransaction, signer Signer) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
+	}
+
+	address := signer.Address()
+	for attempt := 0; ; attempt++ {
+		nonce, err := nm.Next(ctx, address)
+		if err != nil {
+			return "", err
+		}
+
+		withNonce := *tx
+		withNonce.Nonce = nonce
+
+		signed, err := withNonce.SignWith(ctx, signer)
+		if err == nil {
+			err = nm.client.checkBroadcast(signed)
+		}
+		if err != nil {
+			nm.Release(address, nonce)
+			return "", err
+		}
+
+		hash, err := nm.client.BroadcastSignedTransaction(ctx, signed)
+		if err == nil {
+			return hash, nil
+		}
+
+		switch {
+		case errors.Is(err, ErrNonceTooLow):
+		case broadcastRejected(err):
+			nm.Release(address, nonce)
+			return "", err
+		default:
+			return signed.Hash, fmt.Errorf("%w: nonce %d stays reserved: %w", ErrBroadcastUnknown, nonce, err)
+		}
+
+		if attempt+1 >= maxNonceRetries {
+			return "", fmt.Errorf("nonce %d rejected after %d attempts: %w", nonce, attempt+1, err)
+		}
+
+		if err := nm.advance(ctx, address); err != nil {
+			return "", err
+		}
+	}
+}
+
+// broadcastRejected reports whether a failed broadcast was definitely not
+// accepted: the circuit breaker stopped it, or the node answered a single
+// attempt with a JSON-RPC error or the gateway with a 4xx status. Timeouts,
+// 5xx statuses and unusable responses leave the outcome open.
+func broadcastRejected(err error) bool {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Attempts > 1 {
+		return false
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 400 && httpErr.StatusCode < 500
+	}
+
+	var jsonErr *JSONRPCError
+	return errors.As(err, &jsonErr)
+}
@@ -0,0 +1,198 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTransferHash returns the hash newTestTransfer has with nonce
+func testTransferHash(t *testing.T, nonce uint64) string {
+	t.Helper()
+
+	tx := newTestTransfer(t)
+	tx.Nonce = nonce
+	hash, err := tx.Hash()
+	require.NoError(t, err)
+	return hash
+}
+
+func TestNonceManagerBroadcastOutcomes(t *testing.T) {
+	tests := []struct {
+		name        string
+		send        func(w http.ResponseWriter, req *testRPCRequest)
+		wantUnknown bool
+		wantNext    uint64
+	}{
+		{
+			name: "accepted",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.reply(w, map[string]string{"hash": testTransferHash(t, 5)})
+			},
+			wantNext: 6,
+		},
+		{
+			name: "rejected by the node",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.fail(w, -32000, "insufficient funds")
+			},
+			wantNext: 5,
+		},
+		{
+			name: "rejected by the gateway",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				http.Error(w, "bad request", http.StatusBadRequest)
+			},
+			wantNext: 5,
+		},
+		{
+			name: "rate limited",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				http.Error(w, "slow down", http.StatusTooManyRequests)
+			},
+			wantNext: 5,
+		},
+		{
+			name: "gateway error",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				http.Error(w, "upstream failed", http.StatusBadGateway)
+			},
+			wantUnknown: true,
+			wantNext:    6,
+		},
+		{
+			name: "hash mismatch",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.reply(w, map[string]string{"hash": testTransferHash(t, 6)})
+			},
+			wantUnknown: true,
+			wantNext:    6,
+		},
+		{
+			name: "timeout",
+			send: func(w http.ResponseWriter, req *testRPCRequest) {
+				time.Sleep(100 * time.Millisecond)
+			},
+			wantUnknown: true,
+			wantNext:    6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+				switch req.Method {
+				case "getNonce":
+					req.reply(w, 5)
+				case "sendTransaction":
+					tt.send(w, req)
+				}
+			})
+			client := newTestClient(t, server, &ClientConfig{Timeout: 50 * time.Millisecond})
+
+			signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+			require.NoError(t, err)
+
+			nonces := NewNonceManager(client)
+			hash, err := nonces.SignAndBroadcast(context.Background(), newTestTransfer(t), signer)
+
+			switch {
+			case tt.wantUnknown:
+				assert.ErrorIs(t, err, ErrBroadcastUnknown)
+				assert.Equal(t, testTransferHash(t, 5), hash)
+			case tt.wantNext == 5:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, ErrBroadcastUnknown)
+				assert.Empty(t, hash)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, testTransferHash(t, 5), hash)
+			}
+
+			next, err := nonces.Next(context.Background(), signer.Address())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNext, next)
+		})
+	}
+}
+
+func TestNonceManagerCatchesUpOnNonceTooLow(t *testing.T) {
+	var nonceCalls int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		switch req.Method {
+		case "getNonce":
+			// The account sent two transactions elsewhere after the first fetch
+			if atomic.AddInt32(&nonceCalls, 1) == 1 {
+				req.reply(w, 5)
+			} else {
+				req.reply(w, 7)
+			}
+		case "sendTransaction":
+			var params struct {
+				Nonce uint64 `json:"nonce"`
+			}
+			req.param(t, &params)
+			if params.Nonce < 7 {
+				req.fail(w, -32000, "nonce too low")
+				return
+			}
+			req.reply(w, map[string]string{"hash": testTransferHash(t, params.Nonce)})
+		}
+	})
+	client := newTestClient(t, server, nil)
+
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	nonces := NewNonceManager(client)
+	hash, err := nonces.SignAndBroadcast(context.Background(), newTestTransfer(t), signer)
+	require.NoError(t, err)
+	assert.Equal(t, testTransferHash(t, 7), hash)
+}
+
+func TestNonceManagerConcurrentNext(t *testing.T) {
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		req.reply(w, 100)
+	})
+	nonces := NewNonceManager(newTestClient(t, server, nil))
+
+	const count = 50
+	var (
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := nonces.Next(context.Background(), testMainnetAddress)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			seen[nonce] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, seen, count)
+	for nonce := uint64(100); nonce < 100+count; nonce++ {
+		assert.True(t, seen[nonce], nonce)
+	}
+
+	// Released nonces are handed out again, lowest first
+	nonces.Release(testMainnetAddress, 120)
+	nonces.Release(testMainnetAddress, 110)
+	for _, want := range []uint64{110, 120, 150} {
+		next, err := nonces.Next(context.Background(), testMainnetAddress)
+		require.NoError(t, err)
+		assert.Equal(t, want, next)
+	}
+}
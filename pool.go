@@ -0,0 +1,411 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolOptions controls how calls are spread across the nodes listed in
+// ClientConfig.Endpoints. Zero fields take the defaults from
+// DefaultPoolOptions.
+type PoolOptions struct {
+	// Strategy picks the endpoint for each call among the healthy ones
+	Strategy SelectionStrategy
+
+	// HealthCheckInterval is the delay between getNetworkStatus checks of
+	// every endpoint
+	HealthCheckInterval time.Duration
+
+	// HealthCheckTimeout bounds each check
+	HealthCheckTimeout time.Duration
+
+	// MaxBlockLag is how many blocks an endpoint may trail the highest one
+	// seen before it is ejected
+	MaxBlockLag uint64
+
+	// MaxFailures is how many calls in a row may fail with transport errors
+	// or 5xx statuses before an endpoint is ejected until its next passing
+	// health check
+	MaxFailures int
+}
+
+// DefaultPoolOptions returns the options used for zero PoolOptions fields
+func DefaultPoolOptions() PoolOptions {
+	return PoolOptions{
+		Strategy:            RoundRobin(),
+		HealthCheckInterval: 10 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		MaxBlockLag:         5,
+		MaxFailures:         3,
+	}
+}
+
+// withDefaults fills zero fields from DefaultPoolOptions
+func (o *PoolOptions) withDefaults() PoolOptions {
+	defaults := DefaultPoolOptions()
+	if o == nil {
+		return defaults
+	}
+
+	opts := *o
+	if opts.Strategy == nil {
+		opts.Strategy = defaults.Strategy
+	}
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = defaults.HealthCheckInterval
+	}
+	if opts.HealthCheckTimeout <= 0 {
+		opts.HealthCheckTimeout = defaults.HealthCheckTimeout
+	}
+	if opts.MaxBlockLag == 0 {
+		opts.MaxBlockLag = defaults.MaxBlockLag
+	}
+	if opts.MaxFailures <= 0 {
+		opts.MaxFailures = defaults.MaxFailures
+	}
+	return opts
+}
+
+// EndpointStatus is what the client knows about one endpoint
+type EndpointStatus struct {
+	URL string
+
+	// Healthy is false while the endpoint is ejected
+	Healthy bool
+
+	// Syncing and BlockHeight are from the last successful health check
+	Syncing     bool
+	BlockHeight uint64
+
+	// Latency is a moving average of successful calls and checks
+	Latency time.Duration
+
+	// Failures is the number of calls in a row that failed
+	Failures int
+
+	// LastError is the error of the last failed call or check
+	LastError error
+
+	// LastChecked is when the last health check completed
+	LastChecked time.Time
+
+	// Circuit is the state of the endpoint's circuit breaker
+	Circuit CircuitState
+}
+
+// SelectionStrategy picks the endpoint for a call
+type SelectionStrategy interface {
+	// Select returns the index in candidates of the endpoint to use.
+	// candidates is never empty.
+	Select(candidates []EndpointStatus) int
+}
+
+// RoundRobin returns a strategy that takes turns between endpoints
+func RoundRobin() SelectionStrategy {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next uint64
+}
+
+func (s *roundRobin) Select(candidates []EndpointStatus) int {
+	n := atomic.AddUint64(&s.next, 1) - 1
+	return int(n % uint64(len(candidates)))
+}
+
+// LowestLatency returns a strategy that prefers the fastest endpoint.
+// Endpoints not measured yet are tried first.
+func LowestLatency() SelectionStrategy {
+	return lowestLatency{}
+}
+
+type lowestLatency struct{}
+
+func (lowestLatency) Select(candidates []EndpointStatus) int {
+	best := 0
+	for i, candidate := range candidates {
+		if candidate.Latency < candidates[best].Latency {
+			best = i
+		}
+	}
+	return best
+}
+
+// HighestBlock returns a strategy that prefers the endpoint with the most
+// recent block, and the fastest of those
+func HighestBlock() SelectionStrategy {
+	return highestBlock{}
+}
+
+type highestBlock struct{}
+
+func (highestBlock) Select(candidates []EndpointStatus) int {
+	best := 0
+	for i, candidate := range candidates {
+		switch {
+		case candidate.BlockHeight > candidates[best].BlockHeight:
+			best = i
+		case candidate.BlockHeight == candidates[best].BlockHeight && candidate.Latency < candidates[best].Latency:
+			best = i
+		}
+	}
+	return best
+}
+
+// latencyWeight is the weight of the newest sample in the latency average
+const latencyWeight = 0.3
+
+// endpoint is one node in a pool
+type endpoint struct {
+	mu     sync.Mutex
+	status EndpointStatus
+
+	// breaker is nil unless a circuit breaker is configured
+	breaker *circuitBreaker
+}
+
+func (e *endpoint) url() string {
+	return e.status.URL
+}
+
+func (e *endpoint) snapshot() EndpointStatus {
+	e.mu.Lock()
+	status := e.status
+	e.mu.Unlock()
+
+	status.Circuit = e.breaker.currentState()
+	return status
+}
+
+// observeLatency folds a successful round trip into the latency average
+func (e *endpoint) observeLatency(d time.Duration) {
+	if e.status.Latency == 0 {
+		e.status.Latency = d
+		return
+	}
+	e.status.Latency = time.Duration(latencyWeight*float64(d) + (1-latencyWeight)*float64(e.status.Latency))
+}
+
+// endpointPool tracks the health of a set of endpoints and picks one for
+// each call
+type endpointPool struct {
+	endpoints []*endpoint
+	options   PoolOptions
+
+	checking  bool
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newEndpointPool creates a pool; all endpoints start out healthy
+func newEndpointPool(urls []string, options *PoolOptions) *endpointPool {
+	pool := &endpointPool{
+		options: options.withDefaults(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, url := range urls {
+		pool.endpoints = append(pool.endpoints, &endpoint{
+			status: EndpointStatus{URL: url, Healthy: true},
+		})
+	}
+	return pool
+}
+
+// pick returns the endpoint for the next attempt. Endpoints in exclude,
+// typically those that already failed this call, and ejected endpoints are
+// only used if there is nothing else, and endpoints whose circuit is open
+// only if every circuit is.
+func (p *endpointPool) pick(exclude map[*endpoint]bool) *endpoint {
+	if len(p.endpoints) == 1 {
+		return p.endpoints[0]
+	}
+
+	now := time.Now()
+	var healthy, fresh, ready []*endpoint
+	for _, e := range p.endpoints {
+		if !e.breaker.ready(now) {
+			continue
+		}
+		ready = append(ready, e)
+		if exclude[e] {
+			continue
+		}
+		fresh = append(fresh, e)
+		if e.snapshot().Healthy {
+			healthy = append(healthy, e)
+		}
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = fresh
+	}
+	if len(candidates) == 0 {
+		candidates = ready
+	}
+	if len(candidates) == 0 {
+		candidates = p.endpoints
+	}
+
+	statuses := make([]EndpointStatus, len(candidates))
+	for i, e := range candidates {
+		statuses[i] = e.snapshot()
+	}
+
+	i := p.options.Strategy.Select(statuses)
+	if i < 0 || i >= len(candidates) {
+		i = 0
+	}
+	return candidates[i]
+}
+
+// report records the outcome of a call to e
+func (p *endpointPool) report(e *endpoint, duration time.Duration, err error) {
+	e.breaker.record(time.Now(), err)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err == nil || !isEndpointFailure(err) {
+		e.status.Failures = 0
+		e.observeLatency(duration)
+		return
+	}
+
+	e.status.Failures++
+	e.status.LastError = err
+	if e.status.Failures >= p.options.MaxFailures {
+		e.status.Healthy = false
+	}
+}
+
+// isEndpointFailure reports whether err says something about the health of
+// the endpoint, rather than about the request
+func isEndpointFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return isTransportError(err)
+}
+
+// startHealthChecks checks every endpoint with check in the background
+// until the pool is closed
+func (p *endpointPool) startHealthChecks(check func(ctx context.Context, url string) (*NetworkStatus, error)) {
+	p.checking = true
+	go p.run(check)
+}
+
+// run checks every endpoint at the health check interval until the pool
+// is closed
+func (p *endpointPool) run(check func(ctx context.Context, url string) (*NetworkStatus, error)) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.options.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.checkAll(check)
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll checks all endpoints at once, then ejects those that failed,
+// are syncing or lag behind, and re-admits the rest
+func (p *endpointPool) checkAll(check func(ctx context.Context, url string) (*NetworkStatus, error)) {
+	statuses := make([]*NetworkStatus, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), p.options.HealthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			statuses[i], errs[i] = check(ctx, e.url())
+			if errs[i] == nil {
+				e.mu.Lock()
+				e.observeLatency(time.Since(start))
+				e.mu.Unlock()
+			}
+		}(i, e)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i, status := range statuses {
+		if errs[i] == nil && status.BlockHeight > highest {
+			highest = status.BlockHeight
+		}
+	}
+
+	now := time.Now()
+	for i, e := range p.endpoints {
+		e.mu.Lock()
+		e.status.LastChecked = now
+		if errs[i] != nil {
+			e.status.Healthy = false
+			e.status.LastError = errs[i]
+		} else {
+			status := statuses[i]
+			e.status.Syncing = status.Syncing
+			e.status.BlockHeight = status.BlockHeight
+			e.status.Healthy = !status.Syncing && highest-status.BlockHeight <= p.options.MaxBlockLag
+			if e.status.Healthy {
+				e.status.Failures = 0
+			}
+		}
+		e.mu.Unlock()
+	}
+}
+
+// setCircuitBreaker gives every endpoint a circuit breaker with options,
+// or removes them if options is nil
+func (p *endpointPool) setCircuitBreaker(options *CircuitBreakerOptions) {
+	for _, e := range p.endpoints {
+		e.breaker = nil
+		if options != nil {
+			e.breaker = newCircuitBreaker(e.url(), options.withDefaults())
+		}
+	}
+}
+
+// statuses returns the status of every endpoint
+func (p *endpointPool) statuses() []EndpointStatus {
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		statuses[i] = e.snapshot()
+	}
+	return statuses
+}
+
+// close stops health checks, if they were started, and waits for them
+func (p *endpointPool) close() {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		if p.checking {
+			<-p.done
+		}
+	})
+}
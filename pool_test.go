@@ -0,0 +1,275 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectionStrategies(t *testing.T) {
+	candidates := []EndpointStatus{
+		{URL: "a", BlockHeight: 100, Latency: 30 * time.Millisecond},
+		{URL: "b", BlockHeight: 101, Latency: 50 * time.Millisecond},
+		{URL: "c", BlockHeight: 101, Latency: 20 * time.Millisecond},
+		{URL: "d", BlockHeight: 99, Latency: 10 * time.Millisecond},
+	}
+
+	tests := []struct {
+		name     string
+		strategy SelectionStrategy
+		want     []int
+	}{
+		{name: "round robin", strategy: RoundRobin(), want: []int{0, 1, 2, 3, 0}},
+		{name: "lowest latency", strategy: LowestLatency(), want: []int{3, 3}},
+		{name: "highest block", strategy: HighestBlock(), want: []int{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for range tt.want {
+				got = append(got, tt.strategy.Select(candidates))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("unmeasured first", func(t *testing.T) {
+		unmeasured := append([]EndpointStatus{}, candidates...)
+		unmeasured[1].Latency = 0
+		assert.Equal(t, 1, LowestLatency().Select(unmeasured))
+	})
+}
+
+// firstStrategy always selects the first candidate, so tests can see which
+// endpoints pick considered
+type firstStrategy struct{}
+
+func (firstStrategy) Select([]EndpointStatus) int { return 0 }
+
+func TestEndpointPoolPick(t *testing.T) {
+	newPool := func() *endpointPool {
+		return newEndpointPool([]string{"a", "b", "c"}, &PoolOptions{Strategy: firstStrategy{}})
+	}
+
+	t.Run("healthy first", func(t *testing.T) {
+		pool := newPool()
+		pool.endpoints[0].status.Healthy = false
+		assert.Equal(t, "b", pool.pick(nil).url())
+	})
+
+	t.Run("excluded skipped", func(t *testing.T) {
+		pool := newPool()
+		exclude := map[*endpoint]bool{pool.endpoints[0]: true, pool.endpoints[1]: true}
+		assert.Equal(t, "c", pool.pick(exclude).url())
+	})
+
+	t.Run("ejected before excluded", func(t *testing.T) {
+		// With every healthy endpoint already tried, an ejected one that
+		// was not is preferred over trying again
+		pool := newPool()
+		pool.endpoints[2].status.Healthy = false
+		exclude := map[*endpoint]bool{pool.endpoints[0]: true, pool.endpoints[1]: true}
+		assert.Equal(t, "c", pool.pick(exclude).url())
+	})
+
+	t.Run("everything excluded", func(t *testing.T) {
+		pool := newPool()
+		exclude := map[*endpoint]bool{}
+		for _, e := range pool.endpoints {
+			exclude[e] = true
+		}
+		assert.Equal(t, "a", pool.pick(exclude).url())
+	})
+
+	t.Run("open circuit skipped", func(t *testing.T) {
+		pool := newPool()
+		pool.setCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: 1, CoolDown: time.Hour})
+		pool.report(pool.endpoints[0], time.Millisecond, io.EOF)
+
+		// a is open; even the excluded b beats it
+		exclude := map[*endpoint]bool{pool.endpoints[1]: true, pool.endpoints[2]: true}
+		assert.Equal(t, "b", pool.pick(exclude).url())
+	})
+
+	t.Run("every circuit open", func(t *testing.T) {
+		pool := newPool()
+		pool.setCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: 1, CoolDown: time.Hour})
+		for _, e := range pool.endpoints {
+			pool.report(e, time.Millisecond, io.EOF)
+		}
+		assert.Equal(t, "a", pool.pick(nil).url())
+	})
+
+	t.Run("single endpoint", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a"}, nil)
+		pool.endpoints[0].status.Healthy = false
+		assert.Equal(t, "a", pool.pick(nil).url())
+	})
+}
+
+func TestEndpointPoolReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantEjected bool
+	}{
+		{name: "transport error", err: fmt.Errorf("RPC request failed: %w", io.ErrUnexpectedEOF), wantEjected: true},
+		{name: "server error", err: &HTTPError{StatusCode: http.StatusBadGateway}, wantEjected: true},
+		{name: "client error", err: &HTTPError{StatusCode: http.StatusBadRequest}},
+		{name: "JSON-RPC error", err: &JSONRPCError{Code: -32602, Message: "invalid params"}},
+		{name: "cancelled", err: fmt.Errorf("RPC request failed: %w", context.Canceled)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newEndpointPool([]string{"a", "b"}, &PoolOptions{MaxFailures: 2})
+			e := pool.endpoints[0]
+
+			pool.report(e, time.Millisecond, tt.err)
+			assert.True(t, e.snapshot().Healthy, "one failure is tolerated")
+
+			pool.report(e, time.Millisecond, tt.err)
+			status := e.snapshot()
+			assert.Equal(t, !tt.wantEjected, status.Healthy)
+			if tt.wantEjected {
+				assert.Equal(t, 2, status.Failures)
+				assert.Equal(t, tt.err, status.LastError)
+			} else {
+				assert.Zero(t, status.Failures)
+			}
+		})
+	}
+
+	t.Run("success resets failures", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b"}, &PoolOptions{MaxFailures: 2})
+		e := pool.endpoints[0]
+
+		pool.report(e, time.Millisecond, io.EOF)
+		pool.report(e, 10*time.Millisecond, nil)
+		pool.report(e, time.Millisecond, io.EOF)
+
+		status := e.snapshot()
+		assert.True(t, status.Healthy)
+		assert.Equal(t, 1, status.Failures)
+		assert.Equal(t, 10*time.Millisecond, status.Latency)
+	})
+
+	t.Run("latency average", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b"}, nil)
+		e := pool.endpoints[0]
+
+		pool.report(e, 10*time.Millisecond, nil)
+		pool.report(e, 20*time.Millisecond, nil)
+		assert.Equal(t, 13*time.Millisecond, e.snapshot().Latency)
+	})
+}
+
+func TestEndpointPoolCheckAll(t *testing.T) {
+	errDown := errors.New("connection refused")
+	checks := map[string]*NetworkStatus{
+		"ahead":   {BlockHeight: 110},
+		"lagging": {BlockHeight: 104},
+		"close":   {BlockHeight: 105},
+		"syncing": {BlockHeight: 110, Syncing: true},
+		"down":    nil,
+	}
+	check := func(ctx context.Context, url string) (*NetworkStatus, error) {
+		if status := checks[url]; status != nil {
+			return status, nil
+		}
+		return nil, errDown
+	}
+
+	pool := newEndpointPool([]string{"ahead", "lagging", "close", "syncing", "down"}, &PoolOptions{MaxBlockLag: 5})
+	for _, e := range pool.endpoints {
+		e.status.Failures = 2
+	}
+	pool.checkAll(check)
+
+	want := map[string]bool{"ahead": true, "lagging": false, "close": true, "syncing": false, "down": false}
+	for _, status := range pool.statuses() {
+		assert.Equal(t, want[status.URL], status.Healthy, status.URL)
+		assert.False(t, status.LastChecked.IsZero(), status.URL)
+		if status.Healthy {
+			assert.Zero(t, status.Failures, status.URL)
+		}
+	}
+	assert.Equal(t, errDown, pool.statuses()[4].LastError)
+	assert.True(t, pool.statuses()[3].Syncing)
+
+	// Recovered endpoints are re-admitted by the next check
+	checks["lagging"] = &NetworkStatus{BlockHeight: 110}
+	checks["down"] = &NetworkStatus{BlockHeight: 109}
+	pool.checkAll(check)
+	assert.True(t, pool.statuses()[1].Healthy)
+	assert.True(t, pool.statuses()[4].Healthy)
+}
+
+func TestPooledClientFailover(t *testing.T) {
+	var (
+		down  int32
+		calls [2]int32
+	)
+	newServer := func(i int) string {
+		return newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+			if i == 0 && atomic.LoadInt32(&down) == 1 {
+				http.Error(w, "upstream failed", http.StatusBadGateway)
+				return
+			}
+			if req.Method == "getNetworkStatus" {
+				req.reply(w, map[string]interface{}{"block_height": 100})
+				return
+			}
+			atomic.AddInt32(&calls[i], 1)
+			req.reply(w, 100)
+		}).URL
+	}
+	endpoints := []string{newServer(0), newServer(1)}
+
+	client := newTestClient(t, newTestRPCServer(t, nil), &ClientConfig{
+		Endpoints: endpoints,
+		Pool: &PoolOptions{
+			HealthCheckInterval: 10 * time.Millisecond,
+			MaxFailures:         1,
+		},
+	})
+	getBlockHeight := func() {
+		t.Helper()
+		var height uint64
+		require.NoError(t, client.rpcClient.Call(context.Background(), "getBlockHeight", nil, &height))
+	}
+
+	getBlockHeight()
+	getBlockHeight()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls[0]), "calls take turns while both are healthy")
+
+	// Calls fail over to the second node, and the first is ejected
+	atomic.StoreInt32(&down, 1)
+	for i := 0; i < 4; i++ {
+		getBlockHeight()
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls[0]))
+	assert.EqualValues(t, 5, atomic.LoadInt32(&calls[1]))
+	assert.False(t, client.Endpoints()[0].Healthy)
+	assert.Error(t, client.Endpoints()[0].LastError)
+
+	// Once the node recovers, the next health check re-admits it
+	atomic.StoreInt32(&down, 0)
+	require.Eventually(t, func() bool {
+		return client.Endpoints()[0].Healthy
+	}, time.Second, 5*time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		getBlockHeight()
+	}
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls[0]))
+}
@@ -2,9 +2,11 @@ package chert
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 )
 
@@ -46,7 +48,10 @@ func (pm *PrivacyManager) GenerateStealthKeys() (*StealthKeys, error) {
 func (pm *PrivacyManager) CreateStealthAccount(viewKey, spendPublicKey string, keys *StealthKeys) (*StealthAccount, error) {
 	// Generate a deterministic address from the keys
 	hash := sha256.Sum256([]byte(viewKey + spendPublicKey))
-	address := "stealth_" + hex.EncodeToString(hash[:20])
+	address, err := EncodeAddress(AddressKindStealth, pm.client.config.Network, hash[:AddressPayloadSize])
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate stealth address: %w", err)
+	}
 
 	return &StealthAccount{
 		Address:        address,
@@ -103,7 +108,9 @@ func (pm *PrivacyManager) DecryptMemo(encryptedMemo, sharedSecret string) (strin
 	return string(decrypted), nil
 }
 
-// SendPrivateTransaction sends a private transaction
+// SendPrivateTransaction sends a private transaction. Only the public halves
+// of the sender and ephemeral keys are sent to the node; the secrets are used
+// locally to derive the memo encryption key.
 func (pm *PrivacyManager) SendPrivateTransaction(ctx context.Context, request *PrivateTransactionRequest, recipientViewKey, recipientSpendKey string) (string, error) {
 	// Generate ephemeral keys for this transaction
 	ephemeralKeys, err := pm.GenerateStealthKeys()
@@ -127,10 +134,10 @@ func (pm *PrivacyManager) SendPrivateTransaction(ctx context.Context, request *P
 	}
 
 	tx := map[string]interface{}{
-		"sender_keys":         request.SenderKeys,
+		"sender_keys":         stealthPublicKeys(request.SenderKeys),
 		"recipient_view_key":  recipientViewKey,
 		"recipient_spend_key": recipientSpendKey,
-		"ephemeral_keys":      ephemeralKeys,
+		"ephemeral_keys":      stealthPublicKeys(*ephemeralKeys),
 		"amount":              request.Amount,
 		"fee":                 request.Fee,
 		"privacy_level":       request.PrivacyLevel,
@@ -142,7 +149,7 @@ func (pm *PrivacyManager) SendPrivateTransaction(ctx context.Context, request *P
 	}
 
 	var result map[string]interface{}
-	err = pm.client.rpcClient.Call(ctx, "sendPrivateTransaction", []interface{}{tx}, &result)
+	requestID, err := pm.client.rpcClient.callWithID(ctx, "sendPrivateTransaction", []interface{}{tx}, &result)
 	if err != nil {
 		return "", err
 	}
@@ -151,7 +158,16 @@ func (pm *PrivacyManager) SendPrivateTransaction(ctx context.Context, request *P
 		return txID, nil
 	}
 
-	return "", fmt.Errorf("invalid private transaction response")
+	return "", invalidResponse("sendPrivateTransaction", requestID, "missing tx_id")
+}
+
+// stealthPublicKeys returns keys in the StealthKeys layout with only their
+// public halves, for sending to a node
+func stealthPublicKeys(keys StealthKeys) map[string]interface{} {
+	return map[string]interface{}{
+		"view_keypair":  map[string]string{"public": keys.ViewKeypair.Public},
+		"spend_keypair": map[string]string{"public": keys.SpendKeypair.Public},
+	}
 }
 
 // GenerateStealthAddress generates a stealth address via RPC
@@ -161,14 +177,14 @@ func (pm *PrivacyManager) GenerateStealthAddress(ctx context.Context, includeSec
 	}
 
 	var result map[string]interface{}
-	err := pm.client.rpcClient.Call(ctx, "privacy_generateStealthAddress", []interface{}{params}, &result)
+	requestID, err := pm.client.rpcClient.callWithID(ctx, "privacy_generateStealthAddress", []interface{}{params}, &result)
 	if err != nil {
 		return nil, err
 	}
 
 	address, ok := result["address"].(string)
 	if !ok {
-		return nil, fmt.Errorf("invalid stealth address response")
+		return nil, invalidResponse("privacy_generateStealthAddress", requestID, "missing address")
 	}
 
 	account := &StealthAccount{
@@ -185,8 +201,16 @@ func (pm *PrivacyManager) GenerateStealthAddress(ctx context.Context, includeSec
 
 	if includeSecrets {
 		if keysData, ok := result["keys"].(map[string]interface{}); ok {
-			// Parse keys if available
-			// This would be more complex in a real implementation
+			keysBytes, err := json.Marshal(keysData)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode stealth keys: %w", err)
+			}
+
+			var keys StealthKeys
+			if err := json.Unmarshal(keysBytes, &keys); err != nil {
+				return nil, fmt.Errorf("invalid stealth keys in response: %w", err)
+			}
+			account.Keys = &keys
 		}
 	}
 
@@ -195,18 +219,10 @@ func (pm *PrivacyManager) GenerateStealthAddress(ctx context.Context, includeSec
 
 // generateKeyPair generates a random keypair for privacy operations
 func (pm *PrivacyManager) generateKeyPair() (string, string, error) {
-	privateKeyBytes := make([]byte, 32)
-	if _, err := rand.Read(privateKeyBytes); err != nil {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
 		return "", "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
-	// For demonstration, use the same bytes for both keys
-	// In a real implementation, this would derive the public key properly
-	publicKeyBytes := make([]byte, 32)
-	copy(publicKeyBytes, privateKeyBytes)
-
-	privateKey := hex.EncodeToString(privateKeyBytes)
-	publicKey := hex.EncodeToString(publicKeyBytes)
-
-	return privateKey, publicKey, nil
+	return KeyPairFromSeed(seed)
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package chert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendPrivateTransactionKeepsSecrets(t *testing.T) {
+	var sent json.RawMessage
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		req.param(t, &sent)
+		req.reply(w, map[string]string{"tx_id": "private-1"})
+	})
+	client := newTestClient(t, server, nil)
+
+	keys, err := client.Privacy.GenerateStealthKeys()
+	require.NoError(t, err)
+	for _, pair := range []KeyPair{keys.ViewKeypair, keys.SpendKeypair} {
+		public, err := DerivePublicKey(pair.Secret)
+		require.NoError(t, err)
+		assert.Equal(t, public, pair.Public)
+	}
+
+	txID, err := client.Privacy.SendPrivateTransaction(context.Background(), &PrivateTransactionRequest{
+		SenderKeys: *keys,
+		Amount:     MustParseAmount("1"),
+		Fee:        MustParseAmount("0.01"),
+		Memo:       "hello",
+		Nonce:      3,
+	}, "recipient_view_key", "recipient_spend_key")
+	require.NoError(t, err)
+	assert.Equal(t, "private-1", txID)
+
+	var params struct {
+		SenderKeys    StealthKeys `json:"sender_keys"`
+		EphemeralKeys StealthKeys `json:"ephemeral_keys"`
+		EncryptedMemo string      `json:"encrypted_memo"`
+	}
+	require.NoError(t, json.Unmarshal(sent, &params))
+	assert.Equal(t, keys.ViewKeypair.Public, params.SenderKeys.ViewKeypair.Public)
+	assert.Equal(t, keys.SpendKeypair.Public, params.SenderKeys.SpendKeypair.Public)
+	assert.NotEmpty(t, params.EphemeralKeys.ViewKeypair.Public)
+	assert.NotEmpty(t, params.EncryptedMemo)
+
+	// Secret keys hold the seed followed by the public key
+	for _, secret := range []string{keys.ViewKeypair.Secret, keys.SpendKeypair.Secret} {
+		assert.NotContains(t, string(sent), secret[:64])
+	}
+	assert.NotContains(t, string(sent), `"secret"`)
+}
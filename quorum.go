@@ -0,0 +1,185 @@
+package chert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQuorumNotReached is matched by QuorumError
+var ErrQuorumNotReached = errors.New("quorum not reached")
+
+// QuorumOptions makes GetBalance, GetTransaction and GetBlock ask every node
+// in ClientConfig.Endpoints and accept an answer only when enough of them
+// agree
+type QuorumOptions struct {
+	// Required is the number of nodes that must give the same answer. Zero
+	// means a majority of the endpoints.
+	Required int
+}
+
+// quorumMethods are the read methods sent to every node in quorum mode
+var quorumMethods = map[string]bool{
+	"getBalance":     true,
+	"getTransaction": true,
+	"getBlock":       true,
+}
+
+// NodeResponse is one node's answer to a quorum call
+type NodeResponse struct {
+	Endpoint  string
+	RequestID uint64
+
+	// Result is the raw JSON result; empty if the node returned an error
+	Result json.RawMessage
+
+	Err error
+}
+
+// QuorumError is returned when fewer than Required nodes agree, or when the
+// answers are split between several that each reach Required or tie for the
+// most nodes. Responses holds every node's answer, so the disagreement can
+// be inspected.
+type QuorumError struct {
+	Method    string
+	Required  int
+	Agreed    int
+	Responses []NodeResponse
+
+	// Split is set when another answer had as many nodes as the agreed
+	// one, or also reached Required
+	Split bool
+}
+
+func (e *QuorumError) Error() string {
+	if e.Split {
+		return fmt.Sprintf("%s: %v: nodes agreed on more than one answer (%d required) among %d responses",
+			e.Method, ErrQuorumNotReached, e.Required, len(e.Responses))
+	}
+	return fmt.Sprintf("%s: %v: %d of %d required nodes agreed among %d responses",
+		e.Method, ErrQuorumNotReached, e.Agreed, e.Required, len(e.Responses))
+}
+
+// Is makes errors.Is match ErrQuorumNotReached
+func (e *QuorumError) Is(target error) bool {
+	return target == ErrQuorumNotReached
+}
+
+// QuorumCall sends the call to every endpoint at once and decodes the answer
+// that at least required of them agree on. Results agree when their JSON is
+// equal; JSON-RPC errors agree when their code and message are equal, and an
+// agreed error is returned as the call's error. Transport failures never
+// count towards the quorum. If another answer ties the agreed one or also
+// reaches required, the answers are split and a *QuorumError is returned.
+// Each node's call passes through the interceptors but is not retried.
+func (c *RPCClient) QuorumCall(ctx context.Context, required int, method string, params interface{}, result interface{}) error {
+	endpoints := c.pool.endpoints
+	if required <= 0 || required > len(endpoints) {
+		return fmt.Errorf("quorum of %d is impossible with %d endpoints", required, len(endpoints))
+	}
+
+	responses := make([]NodeResponse, len(endpoints))
+	var wg sync.WaitGroup
+	for i, node := range endpoints {
+		wg.Add(1)
+		go func(i int, node *endpoint) {
+			defer wg.Done()
+
+			nodeCtx, state := withCallState(ctx, node)
+			var raw json.RawMessage
+			err := c.chain(false)(nodeCtx, method, params, &raw)
+
+			responses[i] = NodeResponse{Endpoint: node.url(), RequestID: state.lastRequestID(), Result: raw, Err: err}
+		}(i, node)
+	}
+	wg.Wait()
+
+	counts := make(map[string]int)
+	agreed, best := 0, -1
+	for i, response := range responses {
+		key, ok := quorumKey(response)
+		if !ok {
+			continue
+		}
+		counts[key]++
+		if counts[key] > agreed {
+			agreed, best = counts[key], i
+		}
+	}
+
+	if agreed < required {
+		return &QuorumError{Method: method, Required: required, Agreed: agreed, Responses: responses}
+	}
+
+	// With Required at or below half the endpoints, two answers can both
+	// reach it; neither can then be trusted
+	bestKey, _ := quorumKey(responses[best])
+	for key, count := range counts {
+		if key != bestKey && (count == agreed || count >= required) {
+			return &QuorumError{Method: method, Required: required, Agreed: agreed, Responses: responses, Split: true}
+		}
+	}
+
+	winner := responses[best]
+	if winner.Err != nil {
+		return winner.Err
+	}
+	if result == nil || len(winner.Result) == 0 || string(winner.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(winner.Result, result); err != nil {
+		return &RPCError{Method: method, RequestID: winner.RequestID, Err: fmt.Errorf("failed to decode RPC result: %w", err)}
+	}
+	return nil
+}
+
+// quorumKey returns the value responses are compared by: the result in
+// canonical JSON, or the JSON-RPC error. ok is false for responses that
+// carry no answer, such as transport failures.
+func quorumKey(response NodeResponse) (key string, ok bool) {
+	if response.Err != nil {
+		var rpcErr *JSONRPCError
+		if !errors.As(response.Err, &rpcErr) {
+			return "", false
+		}
+		return fmt.Sprintf("error %d %s", rpcErr.Code, rpcErr.Message), true
+	}
+
+	if len(response.Result) == 0 {
+		return "null", true
+	}
+
+	// Decode and re-encode so that key order and whitespace do not matter
+	decoder := json.NewDecoder(bytes.NewReader(response.Result))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(canonical), true
+}
+
+// readCall makes a read call, asking every node if quorum reads are
+// configured for method
+func (c *ChertClient) readCall(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if c.config.Quorum == nil || !quorumMethods[method] {
+		return c.rpcClient.Call(ctx, method, params, result)
+	}
+
+	return c.rpcClient.QuorumCall(ctx, c.quorumRequired(), method, params, result)
+}
+
+// quorumRequired returns the number of nodes that must agree in quorum mode
+func (c *ChertClient) quorumRequired() int {
+	if c.config.Quorum.Required > 0 {
+		return c.config.Quorum.Required
+	}
+	return len(c.rpcClient.pool.endpoints)/2 + 1
+}
@@ -0,0 +1,72 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestQuorumClient starts one node per answer, each replying to every
+// call with its answer, and connects a client to all of them
+func newTestQuorumClient(t *testing.T, answers []int) *ChertClient {
+	t.Helper()
+
+	var (
+		servers   []*httptest.Server
+		endpoints []string
+	)
+	for _, answer := range answers {
+		answer := answer
+		server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+			req.reply(w, answer)
+		})
+		servers = append(servers, server)
+		endpoints = append(endpoints, server.URL)
+	}
+
+	return newTestClient(t, servers[0], &ClientConfig{Endpoints: endpoints})
+}
+
+func TestQuorumCall(t *testing.T) {
+	tests := []struct {
+		name      string
+		answers   []int
+		required  int
+		want      int
+		wantSplit bool
+		wantErr   bool
+	}{
+		{name: "unanimous", answers: []int{1, 1, 1}, required: 3, want: 1},
+		{name: "majority", answers: []int{1, 1, 999}, required: 2, want: 1},
+		{name: "below required", answers: []int{1, 2, 3}, required: 2, wantErr: true},
+		{name: "split evenly", answers: []int{1, 1, 999, 999}, required: 2, wantErr: true, wantSplit: true},
+		{name: "two answers reach required", answers: []int{1, 1, 1, 999, 999}, required: 2, wantErr: true, wantSplit: true},
+		{name: "tie below the winner", answers: []int{1, 1, 1, 2, 3}, required: 2, want: 1},
+		{name: "tie at the top", answers: []int{1, 1, 2, 2, 3}, required: 1, wantErr: true, wantSplit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestQuorumClient(t, tt.answers)
+
+			var result int
+			err := client.RPC().QuorumCall(context.Background(), tt.required, "getBalance", nil, &result)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+				return
+			}
+
+			var quorumErr *QuorumError
+			require.ErrorAs(t, err, &quorumErr)
+			assert.ErrorIs(t, err, ErrQuorumNotReached)
+			assert.Equal(t, tt.wantSplit, quorumErr.Split)
+			assert.Len(t, quorumErr.Responses, len(tt.answers))
+			assert.Zero(t, result)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BuildReplacement returns a copy of original with a higher fee and the same
+// nonce. Once broadcast, whichever of the two is included first wins.
+func (wm *WalletManager) BuildReplacement(original *UnsignedTransaction, fee Amount) (*UnsignedTransaction, error) {
+	if original == nil {
+		return nil, fmt.Errorf("original transaction is nil")
+	}
+
+	if fee.Cmp(original.Fee) <= 0 {
+		return nil, fmt.Errorf("%w: %s <= %s", ErrReplacementUnderpriced, fee, original.Fee)
+	}
+
+	replacement := *original
+	replacement.Fee = fee
+	return checkUnsigned(&replacement)
+}
+
+// BuildCancellation returns a zero-value transfer from the sender of original
+// to itself, with the same nonce and a higher fee. Once included, original
+// can no longer be.
+func (wm *WalletManager) BuildCancellation(original *UnsignedTransaction, fee Amount) (*UnsignedTransaction, error) {
+	if original == nil {
+		return nil, fmt.Errorf("original transaction is nil")
+	}
+
+	if fee.Cmp(original.Fee) <= 0 {
+		return nil, fmt.Errorf("%w: %s <= %s", ErrReplacementUnderpriced, fee, original.Fee)
+	}
+
+	return checkUnsigned(&UnsignedTransaction{
+		Version:   TxEncodingVersion,
+		Type:      TxTypeTransfer,
+		NetworkID: original.NetworkID,
+		From:      original.From,
+		To:        original.From,
+		Fee:       fee,
+		Nonce:     original.Nonce,
+	})
+}
+
+// ReplaceTransaction resends a pending transaction with the same nonce and a
+// higher fee, and returns the hash of the replacement
+func (wm *WalletManager) ReplaceTransaction(ctx context.Context, original *UnsignedTransaction, fee Amount, signer Signer) (string, error) {
+	replacement, err := wm.BuildReplacement(original, fee)
+	if err != nil {
+		return "", err
+	}
+
+	return wm.client.signAndBroadcast(ctx, replacement, signer)
+}
+
+// CancelTransaction sends a zero-value self-transfer with the nonce of a
+// pending transaction and a higher fee, and returns its hash
+func (wm *WalletManager) CancelTransaction(ctx context.Context, original *UnsignedTransaction, fee Amount, signer Signer) (string, error) {
+	cancellation, err := wm.BuildCancellation(original, fee)
+	if err != nil {
+		return "", err
+	}
+
+	return wm.client.signAndBroadcast(ctx, cancellation, signer)
+}
+
+// FeeBumpPolicy controls SendWithFeeBumping. Zero fields take the defaults
+// from DefaultFeeBumpPolicy.
+type FeeBumpPolicy struct {
+	// BumpAfter is how long to wait for confirmation before bumping the fee
+	BumpAfter time.Duration
+
+	// BumpPercent is the minimum fee increase per bump, in percent
+	BumpPercent int64
+
+	// MaxFee caps the fee; zero means no cap
+	MaxFee Amount
+
+	// MaxBumps is the number of replacements sent before giving up
+	MaxBumps int
+
+	// Wait configures polling while waiting. Its Timeout is ignored in
+	// favour of BumpAfter.
+	Wait *WaitOptions
+}
+
+// DefaultFeeBumpPolicy returns the policy used for zero FeeBumpPolicy fields
+func DefaultFeeBumpPolicy() FeeBumpPolicy {
+	return FeeBumpPolicy{
+		BumpAfter:   30 * time.Second,
+		BumpPercent: 20,
+		MaxBumps:    5,
+	}
+}
+
+// withDefaults fills zero fields from DefaultFeeBumpPolicy
+func (p *FeeBumpPolicy) withDefaults() FeeBumpPolicy {
+	defaults := DefaultFeeBumpPolicy()
+	if p == nil {
+		return defaults
+	}
+
+	policy := *p
+	if policy.BumpAfter <= 0 {
+		policy.BumpAfter = defaults.BumpAfter
+	}
+	if policy.BumpPercent <= 0 {
+		policy.BumpPercent = defaults.BumpPercent
+	}
+	if policy.MaxBumps <= 0 {
+		policy.MaxBumps = defaults.MaxBumps
+	}
+	return policy
+}
+
+// nextFee returns the fee for the next replacement of tx: at least
+// BumpPercent above the current fee, raised to the current EstimateFee if
+// that is higher, and capped at MaxFee. If the estimate cannot be fetched
+// the percentage bump alone is used.
+func (wm *WalletManager) nextFee(ctx context.Context, tx *UnsignedTransaction, policy FeeBumpPolicy) Amount {
+	fee := tx.Fee.Mul(100 + policy.BumpPercent).Quo(100)
+	if fee.Cmp(tx.Fee) <= 0 {
+		fee = tx.Fee.Add(NewAmount(1))
+	}
+
+	estimate, err := wm.EstimateFee(ctx, &TransactionRequest{
+		To:     tx.To,
+		Amount: tx.Amount,
+		Fee:    tx.Fee,
+		Memo:   tx.Memo,
+		Nonce:  tx.Nonce,
+	})
+	if err == nil && estimate.Amount.Cmp(fee) > 0 {
+		fee = estimate.Amount
+	}
+
+	if policy.MaxFee.Sign() > 0 && fee.Cmp(policy.MaxFee) > 0 {
+		fee = policy.MaxFee
+	}
+
+	return fee
+}
+
+// SendWithFeeBumping signs and broadcasts tx, then waits for confirmation.
+// Each time BumpAfter passes without confirmation it sends a replacement
+// with a higher fee, until MaxBumps replacements have been sent or the fee
+// reaches MaxFee. Whichever version is confirmed first is returned.
+//
+// It returns ErrWaitTimeout (wrapped) if no version is confirmed after the
+// last bump, and ErrTransactionFailed (wrapped) if one fails.
+func (wm *WalletManager) SendWithFeeBumping(ctx context.Context, tx *UnsignedTransaction, signer Signer, policy *FeeBumpPolicy) (*Transaction, error) {
+	options := policy.withDefaults()
+
+	current := tx
+	hash, err := wm.client.signAndBroadcast(ctx, current, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	var replaced []string
+	for bump := 0; ; bump++ {
+		wait := options.Wait.withDefaults()
+		wait.Timeout = options.BumpAfter
+
+		confirmed, err := wm.WaitForTransaction(ctx, hash, &wait)
+		if !errors.Is(err, ErrWaitTimeout) {
+			return confirmed, err
+		}
+
+		// An earlier version may have been included instead
+		if earlier, err := wm.findIncluded(ctx, replaced, wait.Confirmations); earlier != nil || err != nil {
+			return earlier, err
+		}
+
+		if bump >= options.MaxBumps {
+			return confirmed, err
+		}
+
+		fee := wm.nextFee(ctx, current, options)
+		if fee.Cmp(current.Fee) <= 0 {
+			// Already at MaxFee; keep waiting
+			continue
+		}
+
+		replacement, err := wm.BuildReplacement(current, fee)
+		if err != nil {
+			return nil, err
+		}
+
+		replacementHash, err := wm.client.signAndBroadcast(ctx, replacement, signer)
+		if err != nil {
+			if errors.Is(err, ErrNonceTooLow) {
+				// A version is already included; the next wait finds it
+				continue
+			}
+			return nil, fmt.Errorf("failed to replace transaction %s: %w", hash, err)
+		}
+
+		replaced = append(replaced, hash)
+		hash = replacementHash
+		current = replacement
+	}
+}
+
+// findIncluded returns the first of hashes that is confirmed or failed
+func (wm *WalletManager) findIncluded(ctx context.Context, hashes []string, confirmations uint64) (*Transaction, error) {
+	for _, hash := range hashes {
+		tx, err := wm.pollTransaction(ctx, hash, confirmations)
+		if err == nil || errors.Is(err, ErrTransactionFailed) {
+			return tx, err
+		}
+	}
+	return nil, nil
+}
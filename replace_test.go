@@ -0,0 +1,295 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReplacement(t *testing.T) {
+	client := newTestClient(t, newTestRPCServer(t, nil), nil)
+	original := newTestTransfer(t)
+
+	replacement, err := client.Wallet.BuildReplacement(original, MustParseAmount("0.2"))
+	require.NoError(t, err)
+	assert.Equal(t, original.Nonce, replacement.Nonce)
+	assert.Equal(t, original.To, replacement.To)
+	assert.Zero(t, original.Amount.Cmp(replacement.Amount))
+	assert.Zero(t, MustParseAmount("0.2").Cmp(replacement.Fee))
+	assert.Zero(t, MustParseAmount("0.1").Cmp(original.Fee), "original is not modified")
+
+	for _, fee := range []string{"0.1", "0.05"} {
+		_, err := client.Wallet.BuildReplacement(original, MustParseAmount(fee))
+		assert.ErrorIs(t, err, ErrReplacementUnderpriced, fee)
+	}
+
+	_, err = client.Wallet.BuildReplacement(nil, MustParseAmount("0.2"))
+	assert.Error(t, err)
+}
+
+func TestBuildCancellation(t *testing.T) {
+	client := newTestClient(t, newTestRPCServer(t, nil), nil)
+	original := newTestTransfer(t)
+
+	cancellation, err := client.Wallet.BuildCancellation(original, MustParseAmount("0.2"))
+	require.NoError(t, err)
+	assert.Equal(t, original.Nonce, cancellation.Nonce)
+	assert.Equal(t, original.From, cancellation.From)
+	assert.Equal(t, original.From, cancellation.To)
+	assert.True(t, cancellation.Amount.IsZero())
+	assert.Zero(t, MustParseAmount("0.2").Cmp(cancellation.Fee))
+
+	_, err = client.Wallet.BuildCancellation(original, MustParseAmount("0.1"))
+	assert.ErrorIs(t, err, ErrReplacementUnderpriced)
+}
+
+// testSentTransfer is a transfer as received by newTestFeeBumpServer
+type testSentTransfer struct {
+	Hash   string
+	To     string
+	Amount Amount
+	Fee    Amount
+	Nonce  uint64
+}
+
+// newTestFeeBumpServer accepts transfers of newTestTransfer's account and
+// answers getTransaction with status(hash), where an empty status means not
+// found. estimateFee fails unless estimate is set.
+func newTestFeeBumpServer(t *testing.T, estimate string, status func(hash string, sent []testSentTransfer) TransactionStatus) (*ChertClient, func() []testSentTransfer) {
+	t.Helper()
+
+	var (
+		mu   sync.Mutex
+		sent []testSentTransfer
+	)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req.Method {
+		case "sendTransaction":
+			var params struct {
+				Recipient string `json:"recipient"`
+				Amount    Amount `json:"amount"`
+				Fee       Amount `json:"fee"`
+				Nonce     uint64 `json:"nonce"`
+			}
+			req.param(t, &params)
+
+			tx := newTestTransfer(t)
+			tx.To = params.Recipient
+			tx.Amount = params.Amount
+			tx.Fee = params.Fee
+			tx.Nonce = params.Nonce
+			hash, err := tx.Hash()
+			require.NoError(t, err)
+
+			sent = append(sent, testSentTransfer{Hash: hash, To: tx.To, Amount: tx.Amount, Fee: tx.Fee, Nonce: tx.Nonce})
+			req.reply(w, map[string]string{"hash": hash})
+		case "getTransaction":
+			var hash string
+			req.param(t, &hash)
+			txStatus := status(hash, sent)
+			if txStatus == "" {
+				req.reply(w, nil)
+				return
+			}
+			req.reply(w, map[string]interface{}{
+				"hash":         hash,
+				"from":         testMainnetAddress,
+				"to":           testValidatorAddress,
+				"amount":       "100",
+				"fee":          "0.1",
+				"status":       txStatus,
+				"block_height": 10,
+			})
+		case "estimateFee":
+			if estimate == "" {
+				req.fail(w, -32601, "method not found")
+				return
+			}
+			req.reply(w, map[string]string{"amount": estimate})
+		default:
+			req.fail(w, -32601, "method not found")
+		}
+	})
+
+	client := newTestClient(t, server, &ClientConfig{Retry: &RetryPolicy{MaxAttempts: 1}})
+	return client, func() []testSentTransfer {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]testSentTransfer(nil), sent...)
+	}
+}
+
+func TestReplaceAndCancelTransaction(t *testing.T) {
+	client, sent := newTestFeeBumpServer(t, "", func(string, []testSentTransfer) TransactionStatus { return "" })
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+	original := newTestTransfer(t)
+
+	hash, err := client.Wallet.ReplaceTransaction(context.Background(), original, MustParseAmount("0.2"), signer)
+	require.NoError(t, err)
+
+	hash2, err := client.Wallet.CancelTransaction(context.Background(), original, MustParseAmount("0.3"), signer)
+	require.NoError(t, err)
+
+	transfers := sent()
+	require.Len(t, transfers, 2)
+	assert.Equal(t, hash, transfers[0].Hash)
+	assert.Equal(t, original.To, transfers[0].To)
+	assert.Equal(t, hash2, transfers[1].Hash)
+	assert.Equal(t, original.From, transfers[1].To)
+	assert.True(t, transfers[1].Amount.IsZero())
+	for _, transfer := range transfers {
+		assert.Equal(t, original.Nonce, transfer.Nonce)
+	}
+
+	_, err = client.Wallet.ReplaceTransaction(context.Background(), original, original.Fee, signer)
+	assert.ErrorIs(t, err, ErrReplacementUnderpriced)
+	assert.Len(t, sent(), 2, "underpriced replacement is not sent")
+}
+
+func TestNextFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		estimate string
+		policy   FeeBumpPolicy
+		want     string
+	}{
+		{name: "percentage bump", policy: FeeBumpPolicy{BumpPercent: 20}, want: "0.12"},
+		{name: "estimate below bump", estimate: "0.11", policy: FeeBumpPolicy{BumpPercent: 20}, want: "0.12"},
+		{name: "estimate above bump", estimate: "0.5", policy: FeeBumpPolicy{BumpPercent: 20}, want: "0.5"},
+		{name: "capped", estimate: "0.5", policy: FeeBumpPolicy{BumpPercent: 20, MaxFee: MustParseAmount("0.15")}, want: "0.15"},
+		{name: "cap reached", policy: FeeBumpPolicy{BumpPercent: 20, MaxFee: MustParseAmount("0.1")}, want: "0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestFeeBumpServer(t, tt.estimate, nil)
+
+			fee := client.Wallet.nextFee(context.Background(), newTestTransfer(t), tt.policy)
+			assert.Zero(t, MustParseAmount(tt.want).Cmp(fee), "got %s", fee)
+		})
+	}
+}
+
+func TestNextFeeMinimumBump(t *testing.T) {
+	client, _ := newTestFeeBumpServer(t, "", nil)
+
+	tx := newTestTransfer(t)
+	tx.Fee = NewAmount(1)
+	fee := client.Wallet.nextFee(context.Background(), tx, FeeBumpPolicy{BumpPercent: 20})
+	assert.Zero(t, NewAmount(2).Cmp(fee), "a bump that rounds to nothing still raises the fee by one unit, got %s", fee)
+}
+
+// testFeeBumpPolicy bumps quickly and polls every millisecond
+func testFeeBumpPolicy() *FeeBumpPolicy {
+	return &FeeBumpPolicy{
+		BumpAfter:   20 * time.Millisecond,
+		BumpPercent: 20,
+		MaxBumps:    5,
+		Wait: &WaitOptions{
+			PollInterval:    time.Millisecond,
+			MaxPollInterval: 2 * time.Millisecond,
+		},
+	}
+}
+
+func TestSendWithFeeBumping(t *testing.T) {
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	t.Run("latest replacement confirmed", func(t *testing.T) {
+		// Only the third version, after two bumps, is ever included
+		client, sent := newTestFeeBumpServer(t, "", func(hash string, sent []testSentTransfer) TransactionStatus {
+			if len(sent) == 3 && hash == sent[2].Hash {
+				return TxStatusConfirmed
+			}
+			return TxStatusPending
+		})
+
+		tx, err := client.Wallet.SendWithFeeBumping(context.Background(), newTestTransfer(t), signer, testFeeBumpPolicy())
+		require.NoError(t, err)
+
+		transfers := sent()
+		require.Len(t, transfers, 3)
+		assert.Equal(t, transfers[2].Hash, tx.Hash)
+		for i, want := range []string{"0.1", "0.12", "0.144"} {
+			assert.Equal(t, uint64(7), transfers[i].Nonce)
+			assert.Zero(t, MustParseAmount(want).Cmp(transfers[i].Fee), "fee %d is %s", i, transfers[i].Fee)
+		}
+	})
+
+	t.Run("earlier version included", func(t *testing.T) {
+		// The original is included once the first replacement is sent, and
+		// the replacement is never seen
+		client, sent := newTestFeeBumpServer(t, "", func(hash string, sent []testSentTransfer) TransactionStatus {
+			switch {
+			case hash != sent[0].Hash:
+				return ""
+			case len(sent) > 1:
+				return TxStatusConfirmed
+			}
+			return TxStatusPending
+		})
+
+		tx, err := client.Wallet.SendWithFeeBumping(context.Background(), newTestTransfer(t), signer, testFeeBumpPolicy())
+		require.NoError(t, err)
+
+		transfers := sent()
+		require.Len(t, transfers, 2, "no bump after an earlier version is included")
+		assert.Equal(t, transfers[0].Hash, tx.Hash)
+	})
+
+	t.Run("earlier version failed", func(t *testing.T) {
+		client, sent := newTestFeeBumpServer(t, "", func(hash string, sent []testSentTransfer) TransactionStatus {
+			switch {
+			case hash != sent[0].Hash:
+				return ""
+			case len(sent) > 1:
+				return TxStatusFailed
+			}
+			return TxStatusPending
+		})
+
+		tx, err := client.Wallet.SendWithFeeBumping(context.Background(), newTestTransfer(t), signer, testFeeBumpPolicy())
+		assert.ErrorIs(t, err, ErrTransactionFailed)
+		require.NotNil(t, tx)
+		assert.Equal(t, sent()[0].Hash, tx.Hash)
+	})
+
+	t.Run("max bumps", func(t *testing.T) {
+		client, sent := newTestFeeBumpServer(t, "", func(string, []testSentTransfer) TransactionStatus {
+			return TxStatusPending
+		})
+
+		policy := testFeeBumpPolicy()
+		policy.MaxBumps = 2
+		_, err := client.Wallet.SendWithFeeBumping(context.Background(), newTestTransfer(t), signer, policy)
+		assert.ErrorIs(t, err, ErrWaitTimeout)
+		assert.Len(t, sent(), 3)
+	})
+
+	t.Run("max fee", func(t *testing.T) {
+		client, sent := newTestFeeBumpServer(t, "", func(string, []testSentTransfer) TransactionStatus {
+			return TxStatusPending
+		})
+
+		// One bump reaches the cap; later bumps only keep waiting
+		policy := testFeeBumpPolicy()
+		policy.MaxBumps = 3
+		policy.MaxFee = MustParseAmount("0.11")
+		_, err := client.Wallet.SendWithFeeBumping(context.Background(), newTestTransfer(t), signer, policy)
+		assert.ErrorIs(t, err, ErrWaitTimeout)
+
+		transfers := sent()
+		require.Len(t, transfers, 2)
+		assert.Zero(t, policy.MaxFee.Cmp(transfers[1].Fee))
+	})
+}
@@ -0,0 +1,182 @@
+package chert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxResponseSize is the largest response body read by default
+const DefaultMaxResponseSize int64 = 10 << 20
+
+// maxSnippetSize bounds the part of a response body kept in errors
+const maxSnippetSize = 512
+
+// maxErrorBodySize bounds the part of a non-2xx response body that is read,
+// enough for the JSON-RPC or API error it may hold
+const maxErrorBodySize int64 = 64 << 10
+
+// ErrResponseTooLarge is returned when a response body exceeds the maximum
+// response size. It matches ErrInvalidResponse.
+var ErrResponseTooLarge = fmt.Errorf("%w: response body too large", ErrInvalidResponse)
+
+// HTTPError is returned when the node, or a proxy in front of it, answers
+// with a non-2xx HTTP status
+type HTTPError struct {
+	StatusCode int
+	Status     string
+
+	// Body is the start of the response body, for diagnosis
+	Body string
+
+	// RetryAfter is how long the server asked the client to wait, from the
+	// Retry-After header of 429 and 503 responses; zero if not given
+	RetryAfter time.Duration
+
+	// Err is the JSON-RPC or API error in the body, if the server sent one
+	Err error
+}
+
+func (e *HTTPError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("HTTP %s: %v", e.Status, e.Err)
+	case e.Body != "":
+		return fmt.Sprintf("HTTP %s: %s", e.Status, e.Body)
+	default:
+		return fmt.Sprintf("HTTP %s", e.Status)
+	}
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// readResponse checks the status and content type of resp and decodes its
+// body, of at most the maximum response size, into response. Error
+// responses are read only up to maxErrorBodySize, so an oversized error
+// page still yields an *HTTPError.
+func (c *RPCClient) readResponse(resp *http.Response, response interface{}) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		limit := c.maxResponseSize
+		if limit > maxErrorBodySize {
+			limit = maxErrorBodySize
+		}
+		// A partial body still makes a snippet; read errors are ignored
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, limit))
+
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       snippet(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:        bodyError(body),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read RPC response: %w", err)
+	}
+	if int64(len(body)) > c.maxResponseSize {
+		return fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, c.maxResponseSize)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); !isJSONContentType(contentType) {
+		return fmt.Errorf("%w: unexpected content type %q: %s", ErrInvalidResponse, contentType, snippet(body))
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("%w: failed to decode RPC response: %v: %s", ErrInvalidResponse, err, snippet(body))
+	}
+	return nil
+}
+
+// isJSONContentType reports whether a Content-Type header allows a JSON
+// body. A missing header is accepted.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		mediaType == "application/json-rpc" ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+// bodyError returns the JSON-RPC or API error in an error response body,
+// or nil if it holds neither
+func bodyError(body []byte) error {
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || len(envelope.Error) == 0 {
+		return nil
+	}
+
+	var rpcErr JSONRPCError
+	if err := json.Unmarshal(envelope.Error, &rpcErr); err == nil && rpcErr.Message != "" {
+		return &rpcErr
+	}
+
+	var apiErr APIError
+	if err := json.Unmarshal(envelope.Error, &apiErr); err == nil && apiErr.Code != "" {
+		return &apiErr
+	}
+	return nil
+}
+
+// snippet returns the start of body as trimmed, valid UTF-8
+func snippet(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) <= maxSnippetSize {
+		return strings.ToValidUTF8(string(body), "?")
+	}
+
+	cut := maxSnippetSize
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return strings.ToValidUTF8(string(body[:cut]), "?") + "..."
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or
+// as an HTTP date; it returns zero if the header is missing or invalid
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// retryAfter returns the delay requested by the server in err, if any
+func retryAfter(err error) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.RetryAfter
+	}
+	return 0
+}
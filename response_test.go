@@ -0,0 +1,169 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callTestServer makes one getBlockHeight call, without retries, to a server
+// that answers with respond
+func callTestServer(t *testing.T, maxResponseSize int64, respond func(w http.ResponseWriter, req *testRPCRequest)) (uint64, error) {
+	t.Helper()
+
+	server := newTestRPCServer(t, respond)
+	client := newTestClient(t, server, &ClientConfig{
+		Retry:           &RetryPolicy{MaxAttempts: 1},
+		MaxResponseSize: maxResponseSize,
+	})
+
+	var height uint64
+	err := client.RPC().Call(context.Background(), "getBlockHeight", nil, &height)
+	return height, err
+}
+
+func TestReadResponseStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		header      map[string]string
+		body        string
+		wantBody    string
+		wantRPCErr  bool
+		wantBackoff time.Duration
+	}{
+		{
+			name:     "gateway error page",
+			status:   http.StatusBadGateway,
+			header:   map[string]string{"Content-Type": "text/html"},
+			body:     "<html>bad gateway</html>",
+			wantBody: "<html>bad gateway</html>",
+		},
+		{
+			name:        "rate limited",
+			status:      http.StatusTooManyRequests,
+			header:      map[string]string{"Retry-After": "7"},
+			body:        "slow down",
+			wantBody:    "slow down",
+			wantBackoff: 7 * time.Second,
+		},
+		{
+			name:       "JSON-RPC error in body",
+			status:     http.StatusInternalServerError,
+			header:     map[string]string{"Content-Type": "application/json"},
+			body:       `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"node overloaded"}}`,
+			wantRPCErr: true,
+		},
+		{
+			name:        "oversized error page",
+			status:      http.StatusServiceUnavailable,
+			header:      map[string]string{"Retry-After": "30"},
+			body:        strings.Repeat("x", 4096),
+			wantBody:    strings.Repeat("x", maxSnippetSize) + "...",
+			wantBackoff: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := callTestServer(t, 1024, func(w http.ResponseWriter, req *testRPCRequest) {
+				for key, value := range tt.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			var httpErr *HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, tt.status, httpErr.StatusCode)
+			assert.Equal(t, tt.wantBackoff, httpErr.RetryAfter)
+			assert.NotErrorIs(t, err, ErrResponseTooLarge)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, httpErr.Body)
+			}
+
+			var rpcErr *JSONRPCError
+			assert.Equal(t, tt.wantRPCErr, errors.As(err, &rpcErr))
+		})
+	}
+}
+
+func TestReadResponseContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		wantErr     bool
+	}{
+		{contentType: "", wantErr: false},
+		{contentType: "application/json", wantErr: false},
+		{contentType: "application/json; charset=utf-8", wantErr: false},
+		{contentType: "application/json-rpc", wantErr: false},
+		{contentType: "application/vnd.chert+json", wantErr: false},
+		{contentType: "text/plain; charset=utf-8", wantErr: true},
+		{contentType: "text/html", wantErr: true},
+		{contentType: "not a media type;;", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			height, err := callTestServer(t, 0, func(w http.ResponseWriter, req *testRPCRequest) {
+				// Set the header directly, so an empty one stays empty
+				w.Header()["Content-Type"] = []string{tt.contentType}
+				w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":42}`))
+			})
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidResponse)
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, 42, height)
+		})
+	}
+}
+
+func TestReadResponseSizeLimit(t *testing.T) {
+	padded := func(size int) func(w http.ResponseWriter, req *testRPCRequest) {
+		return func(w http.ResponseWriter, req *testRPCRequest) {
+			w.Header().Set("Content-Type", "application/json")
+			body := `{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":42}`
+			w.Write([]byte(body + strings.Repeat(" ", size-len(body))))
+		}
+	}
+
+	height, err := callTestServer(t, 256, padded(256))
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, height)
+
+	_, err = callTestServer(t, 256, padded(257))
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "0", want: 0},
+		{header: "120", want: 2 * time.Minute},
+		{header: " 5 ", want: 5 * time.Second},
+		{header: "-5", want: 0},
+		{header: "soon", want: 0},
+		{header: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{header: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseRetryAfter(tt.header, now), tt.header)
+	}
+}
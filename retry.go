@@ -0,0 +1,260 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how RPC calls are retried after transient failures.
+// Zero fields take the defaults from DefaultRetryPolicy.
+//
+// Read-only methods are retried by default. Methods that change state, such
+// as sendTransaction, are only retried when the context is marked with
+// WithIdempotent.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts per call, including the first.
+	// Set it to 1 to disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+
+	// Multiplier multiplies the delay after every attempt
+	Multiplier float64
+
+	// Jitter randomly shortens each delay by up to this fraction, between 0
+	// and 1, so that clients do not retry in lockstep
+	Jitter float64
+
+	// RetryableStatuses are the HTTP statuses worth retrying
+	RetryableStatuses []int
+
+	// RetryableCodes are the JSON-RPC error codes worth retrying. None are
+	// by default, since the node answered.
+	RetryableCodes []int
+
+	// Retryable, if set, replaces the status and code checks. Transport
+	// errors are always retryable and context errors never are.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns the policy used for zero RetryPolicy fields
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    200 * time.Millisecond,
+		MaxBackoff:        5 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+// withDefaults fills zero fields from DefaultRetryPolicy
+func (p *RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p == nil {
+		return defaults
+	}
+
+	policy := *p
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaults.InitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = policy.InitialBackoff
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = defaults.Multiplier
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = defaults.Jitter
+	}
+	if policy.RetryableStatuses == nil {
+		policy.RetryableStatuses = defaults.RetryableStatuses
+	}
+	return policy
+}
+
+// backoff returns the delay after the given failed attempt, counting from 1
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	delay *= 1 - p.Jitter*rand.Float64()
+	return time.Duration(delay)
+}
+
+// shouldRetry reports whether err is worth another attempt
+func (p *RetryPolicy) shouldRetry(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if isTransportError(err) {
+		return true
+	}
+
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		for _, status := range p.RetryableStatuses {
+			if httpErr.StatusCode == status {
+				return true
+			}
+		}
+		return false
+	}
+
+	var rpcErr *JSONRPCError
+	if errors.As(err, &rpcErr) {
+		for _, code := range p.RetryableCodes {
+			if rpcErr.Code == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTransportError reports whether err means the request or response was
+// lost on the way, rather than answered
+func isTransportError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// idempotentKey marks contexts whose calls are safe to repeat
+type idempotentKey struct{}
+
+// WithIdempotent returns a context under which RPC calls that change state
+// may be retried like reads. Use it only when sending the same request twice
+// has the same effect as sending it once, for example when rebroadcasting an
+// already signed transaction and treating "already known" as success.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent reports whether ctx was marked with WithIdempotent
+func isIdempotent(ctx context.Context) bool {
+	idempotent, _ := ctx.Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// isReadOnlyMethod reports whether an RPC method only reads state. Methods
+// are named like getBalance or governance_getProposal; fee estimation and
+// simulation never change state either.
+func isReadOnlyMethod(method string) bool {
+	if i := strings.LastIndex(method, "_"); i >= 0 {
+		method = method[i+1:]
+	}
+	return strings.HasPrefix(method, "get") ||
+		method == "estimateFee" ||
+		method == "simulateTransaction"
+}
+
+// retryable reports whether calls to all of methods may be retried under ctx
+func retryable(ctx context.Context, methods ...string) bool {
+	if isIdempotent(ctx) {
+		return true
+	}
+	for _, method := range methods {
+		if !isReadOnlyMethod(method) {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryInterceptor retries calls according to policy; nil means
+// DefaultRetryPolicy. Clients apply their own retry policy innermost, so
+// when using this interceptor elsewhere in the chain, set the client's
+// MaxAttempts to 1.
+func RetryInterceptor(policy *RetryPolicy) Interceptor {
+	configured := policy.withDefaults()
+	return func(ctx context.Context, method string, params interface{}, result interface{}, next Invoker) error {
+		p := configured
+		if !retryable(ctx, callMethods(method, params)...) {
+			p.MaxAttempts = 1
+		}
+
+		attempts, err := p.retry(ctx, func() error {
+			return next(ctx, method, params, result)
+		})
+
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			rpcErr.Attempts = attempts
+		}
+		return err
+	}
+}
+
+// callMethods returns the methods a call invokes: the methods of its
+// elements for a batch, and method otherwise
+func callMethods(method string, params interface{}) []string {
+	batch, ok := params.([]BatchElem)
+	if !ok || method != BatchMethod {
+		return []string{method}
+	}
+
+	methods := make([]string, len(batch))
+	for i, elem := range batch {
+		methods[i] = elem.Method
+	}
+	return methods
+}
+
+// retry runs attempt until it succeeds, fails with an error not worth
+// retrying, or the policy runs out of attempts. It returns the last error
+// and the number of attempts made.
+//
+// A Retry-After delay sent by the server is waited out instead of the
+// backoff if it is longer; if it is longer than MaxBackoff too, the error is
+// returned instead.
+func (p *RetryPolicy) retry(ctx context.Context, attempt func() error) (int, error) {
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || n >= p.MaxAttempts || !p.shouldRetry(err) {
+			return n, err
+		}
+
+		delay := p.backoff(n)
+		if requested := retryAfter(err); requested > delay {
+			if requested > p.MaxBackoff {
+				return n, err
+			}
+			delay = requested
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return n, err
+		case <-timer.C:
+		}
+	}
+}
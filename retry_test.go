@@ -0,0 +1,165 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := (&RetryPolicy{RetryableCodes: []int{-32005}}).withDefaults()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"connection closed", fmt.Errorf("RPC request failed: %w", io.ErrUnexpectedEOF), true},
+		{"canceled", fmt.Errorf("RPC request failed: %w", context.Canceled), false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"429", &HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{"502", &HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{"503", &HTTPError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"500", &HTTPError{StatusCode: http.StatusInternalServerError}, false},
+		{"400", &HTTPError{StatusCode: http.StatusBadRequest}, false},
+		{"retryable code", &JSONRPCError{Code: -32005, Message: "limit exceeded"}, true},
+		{"other code", &JSONRPCError{Code: -32000, Message: "insufficient funds"}, false},
+		{"wrapped", &RPCError{Method: "getBalance", Err: &HTTPError{StatusCode: http.StatusBadGateway}}, true},
+		{"invalid response", ErrInvalidResponse, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.shouldRetry(tt.err))
+		})
+	}
+
+	// A custom check replaces the status and code checks, but not the
+	// transport and context rules
+	custom := (&RetryPolicy{Retryable: func(err error) bool { return errors.Is(err, ErrInvalidResponse) }}).withDefaults()
+	assert.True(t, custom.shouldRetry(ErrInvalidResponse))
+	assert.False(t, custom.shouldRetry(&HTTPError{StatusCode: http.StatusBadGateway}))
+	assert.True(t, custom.shouldRetry(io.EOF))
+	assert.False(t, custom.shouldRetry(context.Canceled))
+}
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	var nilPolicy *RetryPolicy
+	assert.Equal(t, DefaultRetryPolicy(), nilPolicy.withDefaults())
+
+	policy := (&RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Millisecond, Jitter: 2}).withDefaults()
+	assert.Equal(t, 5, policy.MaxAttempts)
+	assert.Equal(t, time.Second, policy.MaxBackoff)
+	assert.Equal(t, DefaultRetryPolicy().Jitter, policy.Jitter)
+	assert.Equal(t, DefaultRetryPolicy().RetryableStatuses, policy.RetryableStatuses)
+
+	policy = RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	policy := (&RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}).withDefaults()
+	busy := &HTTPError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 50 * time.Millisecond}
+
+	start := time.Now()
+	attempts, err := policy.retry(context.Background(), func() error { return busy })
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, busy, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// A delay beyond MaxBackoff is not waited out
+	busy.RetryAfter = time.Minute
+	start = time.Now()
+	attempts, err = policy.retry(context.Background(), func() error { return busy })
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, busy, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	policy := (&RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Minute}).withDefaults()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	attempts, err := policy.retry(ctx, func() error { return io.EOF })
+	assert.Equal(t, 1, attempts)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestCallRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		idempotent   bool
+		wantAttempts int32
+	}{
+		{name: "read", method: "getBalance", wantAttempts: 3},
+		{name: "namespaced read", method: "governance_getProposal", wantAttempts: 3},
+		{name: "send", method: "sendTransaction", wantAttempts: 1},
+		{name: "idempotent send", method: "sendTransaction", idempotent: true, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+				if atomic.AddInt32(&attempts, 1) < 3 {
+					http.Error(w, "upstream failed", http.StatusBadGateway)
+					return
+				}
+				req.reply(w, "ok")
+			})
+			client := newTestClient(t, server, nil)
+
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = WithIdempotent(ctx)
+			}
+
+			var result string
+			err := client.RPC().Call(ctx, tt.method, nil, &result)
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+			if tt.wantAttempts < 3 {
+				var rpcErr *RPCError
+				require.ErrorAs(t, err, &rpcErr)
+				assert.Equal(t, int(tt.wantAttempts), rpcErr.Attempts)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", result)
+		})
+	}
+}
+
+func TestCallGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		atomic.AddInt32(&attempts, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	client := newTestClient(t, server, &ClientConfig{
+		Retry: &RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	})
+
+	err := client.RPC().Call(context.Background(), "getBlockHeight", nil, nil)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, 4, rpcErr.Attempts)
+	assert.EqualValues(t, 4, atomic.LoadInt32(&attempts))
+
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+}
@@ -6,45 +6,308 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
 // RPCClient handles JSON-RPC communication with the blockchain
 type RPCClient struct {
-	endpoint string
+	pool     *endpointPool
 	client   *http.Client
+	hooks    *RPCHooks
+	retry    RetryPolicy
+	throttle *throttle
+	nextID   uint64
+
+	interceptors []Interceptor
+
+	// maxResponseSize bounds response bodies, in bytes
+	maxResponseSize int64
 }
 
-// NewRPCClient creates a new RPC client
+// RPCHooks are called around every JSON-RPC call, for logging and tracing.
+// Calls in a batch are reported individually. Hooks must be safe for
+// concurrent use.
+type RPCHooks struct {
+	// OnRequest is called before a request is sent
+	OnRequest func(ctx context.Context, method string, requestID uint64)
+
+	// OnResponse is called when a call completes, with its error if any
+	OnResponse func(ctx context.Context, method string, requestID uint64, duration time.Duration, err error)
+
+	// OnThrottle is called when a request had to wait for the rate limit or
+	// a free request slot, with the time it waited. Batches are reported
+	// once, as method "batch".
+	OnThrottle func(ctx context.Context, method string, wait time.Duration)
+}
+
+// RPCError is returned by RPCClient for a failed call. It records the method
+// and request ID, so failures can be matched with node and gateway logs, and
+// wraps the underlying error, such as a *JSONRPCError.
+type RPCError struct {
+	Method string
+
+	// RequestID is the ID of the last attempt
+	RequestID uint64
+
+	// Attempts is the number of requests sent, including retries
+	Attempts int
+
+	Err error
+}
+
+func (e *RPCError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s (request %d, %d attempts): %v", e.Method, e.RequestID, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("%s (request %d): %v", e.Method, e.RequestID, e.Err)
+}
+
+func (e *RPCError) Unwrap() error {
+	return e.Err
+}
+
+// NewRPCClient creates a new RPC client. Requests carry the SDK's default
+// headers, as with NewClient.
 func NewRPCClient(endpoint string, timeout time.Duration) *RPCClient {
 	return &RPCClient{
-		endpoint: endpoint,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		pool:            newEndpointPool([]string{endpoint}, nil),
+		client:          newHTTPClient(&ClientConfig{Timeout: timeout}),
+		retry:           DefaultRetryPolicy(),
+		throttle:        newThrottle(0, 0, 0),
+		maxResponseSize: DefaultMaxResponseSize,
+	}
+}
+
+// NewPooledRPCClient creates an RPC client that spreads calls across several
+// endpoints and fails over between them. With more than one endpoint, their
+// health is checked in the background until Close is called.
+func NewPooledRPCClient(endpoints []string, timeout time.Duration, options *PoolOptions) (*RPCClient, error) {
+	c, err := newPooledRPCClient(endpoints, newHTTPClient(&ClientConfig{Timeout: timeout}), options)
+	if err != nil {
+		return nil, err
 	}
+
+	c.startHealthChecks()
+	return c, nil
 }
 
-// Call makes a JSON-RPC call to the blockchain
+// newPooledRPCClient creates a pooled client without starting health checks,
+// so that it can be configured first
+func newPooledRPCClient(endpoints []string, client *http.Client, options *PoolOptions) (*RPCClient, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+	for _, endpoint := range endpoints {
+		if endpoint == "" {
+			return nil, fmt.Errorf("endpoint URL is empty")
+		}
+	}
+
+	return &RPCClient{
+		pool:            newEndpointPool(endpoints, options),
+		client:          client,
+		retry:           DefaultRetryPolicy(),
+		throttle:        newThrottle(0, 0, 0),
+		maxResponseSize: DefaultMaxResponseSize,
+	}, nil
+}
+
+// startHealthChecks starts checking endpoints if there is more than one
+func (c *RPCClient) startHealthChecks() {
+	if len(c.pool.endpoints) > 1 {
+		c.pool.startHealthChecks(c.checkEndpoint)
+	}
+}
+
+// Endpoints returns the status of every endpoint the client uses
+func (c *RPCClient) Endpoints() []EndpointStatus {
+	return c.pool.statuses()
+}
+
+// Close stops background health checks. The client can still be used
+// afterwards, without them.
+func (c *RPCClient) Close() error {
+	c.pool.close()
+	return nil
+}
+
+// checkEndpoint fetches the network status of one endpoint, for health
+// checks. The call goes through the interceptors, so that they can add
+// credentials, but not through the retry policy or the endpoint's circuit
+// breaker, and its outcome does not count towards ejecting the endpoint.
+func (c *RPCClient) checkEndpoint(ctx context.Context, url string) (*NetworkStatus, error) {
+	ctx, state := withCallState(ctx, nil)
+	check := c.intercepted(func(ctx context.Context, method string, params interface{}, result interface{}) error {
+		id := c.newID()
+		state.setRequestID(id)
+		c.onRequest(ctx, method, id)
+
+		start := time.Now()
+		err := c.call(ctx, url, id, method, params, result)
+		if err != nil {
+			err = &RPCError{Method: method, RequestID: id, Attempts: 1, Err: err}
+		}
+
+		c.onResponse(ctx, method, id, time.Since(start), err)
+		return err
+	})
+
+	var status NetworkStatus
+	if err := check(ctx, "getNetworkStatus", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetHooks sets the hooks called around every call; nil removes them
+func (c *RPCClient) SetHooks(hooks *RPCHooks) {
+	c.hooks = hooks
+}
+
+// SetLimits caps requests to rate per second, with bursts of up to burst,
+// and to maxInFlight at once. Zero disables the corresponding limit; a zero
+// burst allows one second's worth of requests. Call it before the client is
+// used.
+func (c *RPCClient) SetLimits(rate float64, burst, maxInFlight int) {
+	c.throttle = newThrottle(rate, burst, maxInFlight)
+}
+
+// ThrottleStats reports how much the rate limit and concurrency cap have
+// held requests back
+func (c *RPCClient) ThrottleStats() ThrottleStats {
+	return c.throttle.stats()
+}
+
+// SetCircuitBreaker gives every endpoint a circuit breaker with options;
+// nil removes them. Call it before the client is used.
+func (c *RPCClient) SetCircuitBreaker(options *CircuitBreakerOptions) {
+	c.pool.setCircuitBreaker(options)
+}
+
+// SetMaxResponseSize bounds response bodies to size bytes; zero or less
+// restores DefaultMaxResponseSize
+func (c *RPCClient) SetMaxResponseSize(size int64) {
+	if size <= 0 {
+		size = DefaultMaxResponseSize
+	}
+	c.maxResponseSize = size
+}
+
+// SetRetryPolicy sets how failed calls are retried; nil restores
+// DefaultRetryPolicy
+func (c *RPCClient) SetRetryPolicy(policy *RetryPolicy) {
+	c.retry = policy.withDefaults()
+}
+
+// newID returns the next request ID. IDs increase monotonically per client.
+func (c *RPCClient) newID() uint64 {
+	return atomic.AddUint64(&c.nextID, 1)
+}
+
+// rpcResponse is a JSON-RPC response whose ID and result are decoded later
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *JSONRPCError   `json:"error"`
+}
+
+// requestID parses the response ID; ok is false if it is not a request ID
+// this client could have sent
+func (r *rpcResponse) requestID() (id uint64, ok bool) {
+	raw := string(bytes.TrimSpace(r.ID))
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = unquoted
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	return id, err == nil
+}
+
+// decodeResult decodes the response result into result, if both are set
+func (r *rpcResponse) decodeResult(result interface{}) error {
+	if result == nil || len(r.Result) == 0 || string(r.Result) == "null" {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+// Call makes a JSON-RPC call to the blockchain through the interceptors,
+// retrying transient failures according to the retry policy. Failures are
+// returned as *RPCError.
 func (c *RPCClient) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	_, err := c.callWithID(ctx, method, params, result)
+	return err
+}
+
+// callWithID is Call that also returns the ID of the last request sent, so
+// that problems found in the result can be reported with it
+func (c *RPCClient) callWithID(ctx context.Context, method string, params interface{}, result interface{}) (uint64, error) {
+	ctx, state := withCallState(ctx, nil)
+	err := c.chain(true)(ctx, method, params, result)
+	return state.lastRequestID(), err
+}
+
+// call sends one request with the given ID to url and decodes its response
+func (c *RPCClient) call(ctx context.Context, url string, id uint64, method string, params interface{}, result interface{}) error {
 	request := JSONRPCRequest{
 		JSONRPC: "2.0",
 		Method:  method,
 		Params:  params,
-		ID:      1,
+		ID:      id,
+	}
+
+	var response rpcResponse
+	if err := c.post(ctx, url, method, request, &response); err != nil {
+		return err
+	}
+
+	responseID, ok := response.requestID()
+	if ok && responseID != id {
+		return fmt.Errorf("%w: got response %d", ErrResponseIDMismatch, responseID)
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	// Only error responses may omit the ID, for requests the node could not parse
+	if !ok {
+		return fmt.Errorf("%w: got response %s", ErrResponseIDMismatch, response.ID)
+	}
+
+	if err := response.decodeResult(result); err != nil {
+		return fmt.Errorf("failed to decode RPC result: %w", err)
+	}
+	return nil
+}
+
+// post sends body as a JSON-RPC request to url and decodes the response
+// into response. It waits for the client's rate limit and concurrency cap
+// first.
+func (c *RPCClient) post(ctx context.Context, url, method string, body interface{}, response interface{}) error {
+	waited, err := c.throttle.acquire(ctx)
+	if waited > 0 {
+		c.onThrottle(ctx, method, waited)
+	}
+	if err != nil {
+		return err
 	}
+	defer c.throttle.release()
 
-	requestBody, err := json.Marshal(request)
+	requestBody, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("failed to marshal RPC request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(requestBody))
 	if err != nil {
 		return fmt.Errorf("failed to create RPC request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	setRequestHeaders(ctx, req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -52,22 +315,23 @@ func (c *RPCClient) Call(ctx context.Context, method string, params interface{},
 	}
 	defer resp.Body.Close()
 
-	var rpcResp JSONRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-		return fmt.Errorf("failed to decode RPC response: %w", err)
-	}
+	return c.readResponse(resp, response)
+}
 
-	if rpcResp.Error != nil {
-		return rpcResp.Error
+func (c *RPCClient) onRequest(ctx context.Context, method string, id uint64) {
+	if c.hooks != nil && c.hooks.OnRequest != nil {
+		c.hooks.OnRequest(ctx, method, id)
 	}
+}
 
-	if result != nil && rpcResp.Result != nil {
-		resultBytes, err := json.Marshal(rpcResp.Result)
-		if err != nil {
-			return fmt.Errorf("failed to marshal RPC result: %w", err)
-		}
-		return json.Unmarshal(resultBytes, result)
+func (c *RPCClient) onThrottle(ctx context.Context, method string, wait time.Duration) {
+	if c.hooks != nil && c.hooks.OnThrottle != nil {
+		c.hooks.OnThrottle(ctx, method, wait)
 	}
+}
 
-	return nil
-}
\ No newline at end of file
+func (c *RPCClient) onResponse(ctx context.Context, method string, id uint64, duration time.Duration, err error) {
+	if c.hooks != nil && c.hooks.OnResponse != nil {
+		c.hooks.OnResponse(ctx, method, id, duration, err)
+	}
+}
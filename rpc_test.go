@@ -0,0 +1,189 @@
+package chert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPCClientHeaders(t *testing.T) {
+	headers := make(chan http.Header, 2)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		headers <- req.HTTP.Header
+		req.reply(w, 1)
+	})
+
+	pooled, err := NewPooledRPCClient([]string{server.URL}, time.Second, nil)
+	require.NoError(t, err)
+	defer pooled.Close()
+
+	for _, client := range []*RPCClient{NewRPCClient(server.URL, time.Second), pooled} {
+		var result int
+		require.NoError(t, client.Call(context.Background(), "getBlockHeight", nil, &result))
+
+		header := <-headers
+		assert.Equal(t, UserAgent, header.Get("User-Agent"))
+		assert.Equal(t, "application/json", header.Get("Accept"))
+	}
+}
+
+func TestRPCClientResponseID(t *testing.T) {
+	tests := []struct {
+		name      string
+		respond   func(w http.ResponseWriter, req *testRPCRequest)
+		wantErr   error
+		wantValue uint64
+	}{
+		{
+			name:      "matching ID",
+			respond:   func(w http.ResponseWriter, req *testRPCRequest) { req.reply(w, 42) },
+			wantValue: 42,
+		},
+		{
+			name: "ID as a string",
+			respond: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.ID = json.RawMessage(strconv.Quote(string(req.ID)))
+				req.reply(w, 42)
+			},
+			wantValue: 42,
+		},
+		{
+			name: "another request's ID",
+			respond: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.ID = json.RawMessage("999999")
+				req.reply(w, 42)
+			},
+			wantErr: ErrResponseIDMismatch,
+		},
+		{
+			name: "result without ID",
+			respond: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.ID = json.RawMessage("null")
+				req.reply(w, 42)
+			},
+			wantErr: ErrResponseIDMismatch,
+		},
+		{
+			name: "error without ID",
+			respond: func(w http.ResponseWriter, req *testRPCRequest) {
+				req.ID = json.RawMessage("null")
+				req.fail(w, -32700, "parse error")
+			},
+			wantErr: &JSONRPCError{Code: -32700, Message: "parse error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			height, err := callTestServer(t, 0, tt.respond)
+
+			switch want := tt.wantErr.(type) {
+			case nil:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantValue, height)
+			case *JSONRPCError:
+				var rpcErr *JSONRPCError
+				require.ErrorAs(t, err, &rpcErr)
+				assert.Equal(t, want.Code, rpcErr.Code)
+				assert.NotErrorIs(t, err, ErrResponseIDMismatch)
+			default:
+				assert.ErrorIs(t, err, want)
+				assert.ErrorIs(t, err, ErrInvalidResponse)
+			}
+		})
+	}
+}
+
+func TestRPCClientRequestIDs(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []uint64
+		reported []uint64
+	)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		id, err := strconv.ParseUint(string(req.ID), 10, 64)
+		require.NoError(t, err)
+
+		mu.Lock()
+		received = append(received, id)
+		mu.Unlock()
+		req.reply(w, 1)
+	})
+	client := newTestClient(t, server, &ClientConfig{
+		Hooks: &RPCHooks{
+			OnRequest: func(ctx context.Context, method string, requestID uint64) {
+				mu.Lock()
+				reported = append(reported, requestID)
+				mu.Unlock()
+			},
+		},
+	})
+
+	const calls = 20
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.RPC().Call(context.Background(), "getBlockHeight", nil, nil))
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, calls)
+	assert.ElementsMatch(t, reported, received)
+
+	unique := make(map[uint64]bool)
+	for _, id := range received {
+		unique[id] = true
+	}
+	assert.Len(t, unique, calls)
+}
+
+func TestBatchCallMatchesResponsesByID(t *testing.T) {
+	// The server answers in reverse order, repeats the first answer and
+	// leaves out the call for "missing"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []testRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+
+		var responses []map[string]interface{}
+		for i := len(batch) - 1; i >= 0; i-- {
+			var address string
+			req := &batch[i]
+			req.param(t, &address)
+			if address == "missing" {
+				continue
+			}
+			responses = append(responses, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": address})
+		}
+		responses = append(responses, map[string]interface{}{"jsonrpc": "2.0", "id": batch[0].ID, "result": "duplicate"})
+		writeTestJSON(w, responses)
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server, nil)
+
+	addresses := []string{"a", "missing", "c"}
+	batch := make([]BatchElem, len(addresses))
+	results := make([]string, len(addresses))
+	for i, address := range addresses {
+		batch[i] = BatchElem{Method: "getBalance", Params: []interface{}{address}, Result: &results[i]}
+	}
+	require.NoError(t, client.RPC().BatchCall(context.Background(), batch))
+
+	assert.NoError(t, batch[0].Error)
+	assert.Equal(t, "a", results[0])
+	assert.ErrorIs(t, batch[1].Error, ErrResponseIDMismatch)
+	assert.NoError(t, batch[2].Error)
+	assert.Equal(t, "c", results[2])
+}
@@ -0,0 +1,116 @@
+package chert
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRPCRequest is one JSON-RPC call received by a test server
+type testRPCRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+
+	// HTTP is the request the call arrived in
+	HTTP *http.Request `json:"-"`
+}
+
+// reply answers the call with result
+func (r *testRPCRequest) reply(w http.ResponseWriter, result interface{}) {
+	writeTestJSON(w, map[string]interface{}{"jsonrpc": "2.0", "id": r.ID, "result": result})
+}
+
+// fail answers the call with a JSON-RPC error
+func (r *testRPCRequest) fail(w http.ResponseWriter, code int, message string) {
+	writeTestJSON(w, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      r.ID,
+		"error":   map[string]interface{}{"code": code, "message": message},
+	})
+}
+
+// param decodes the first positional parameter into v
+func (r *testRPCRequest) param(t *testing.T, v interface{}) {
+	t.Helper()
+
+	var params []json.RawMessage
+	require.NoError(t, json.Unmarshal(r.Params, &params))
+	require.NotEmpty(t, params)
+	require.NoError(t, json.Unmarshal(params[0], v))
+}
+
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// newTestRPCServer starts a JSON-RPC server that passes every call to
+// handle. The calls of a batch are handled one by one and their responses
+// sent back as an array.
+func newTestRPCServer(t *testing.T, handle func(w http.ResponseWriter, req *testRPCRequest)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body = bytes.TrimSpace(body)
+		if len(body) == 0 || body[0] != '[' {
+			var req testRPCRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.HTTP = r
+			handle(w, &req)
+			return
+		}
+
+		var batch []*testRPCRequest
+		if err := json.Unmarshal(body, &batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		responses := make([]json.RawMessage, 0, len(batch))
+		for _, req := range batch {
+			req.HTTP = r
+			recorder := httptest.NewRecorder()
+			handle(recorder, req)
+			responses = append(responses, bytes.TrimSpace(recorder.Body.Bytes()))
+		}
+		writeTestJSON(w, responses)
+	}))
+
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient creates a client for server with fast retries. config may
+// set other options; its Endpoint is replaced.
+func newTestClient(t *testing.T, server *httptest.Server, config *ClientConfig) *ChertClient {
+	t.Helper()
+
+	if config == nil {
+		config = &ClientConfig{}
+	}
+	config.Endpoint = server.URL
+	if config.Retry == nil {
+		config.Retry = &RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	}
+
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
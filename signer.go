@@ -0,0 +1,364 @@
+package chert
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Signer produces Ed25519 signatures on behalf of an account. Implementations
+// may hold the key in memory or delegate to a separate signing process.
+type Signer interface {
+	// PublicKey returns the hex-encoded Ed25519 public key
+	PublicKey() string
+
+	// Address returns the account address derived from the public key
+	Address() string
+
+	// Sign returns the Ed25519 signature of message
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// LocalSigner is a Signer that holds its private key in memory
+type LocalSigner struct {
+	key       ed25519.PrivateKey
+	publicKey string
+	address   string
+}
+
+// NewLocalSigner creates an in-memory signer from a hex-encoded private key
+// for an account on network
+func NewLocalSigner(privateKey string, network Network) (*LocalSigner, error) {
+	key, err := decodePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	address, err := GenerateAddress(publicKey, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate address: %w", err)
+	}
+
+	return &LocalSigner{
+		key:       key,
+		publicKey: publicKey,
+		address:   address,
+	}, nil
+}
+
+// Signer returns an in-memory signer for the account's private key on the
+// network of its address
+func (a *Account) Signer() (*LocalSigner, error) {
+	if a.PrivateKey == "" {
+		return nil, fmt.Errorf("account does not have a private key")
+	}
+
+	address, err := ParseAddress(a.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := NewLocalSigner(a.PrivateKey, address.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer.Address() != a.Address {
+		return nil, fmt.Errorf("account private key does not match address %s", a.Address)
+	}
+
+	return signer, nil
+}
+
+// PublicKey returns the hex-encoded public key
+func (s *LocalSigner) PublicKey() string {
+	return s.publicKey
+}
+
+// Address returns the account address
+func (s *LocalSigner) Address() string {
+	return s.address
+}
+
+// Sign signs message with the in-memory key
+func (s *LocalSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, message), nil
+}
+
+// RemoteSignerConfig holds the configuration for a RemoteSigner
+type RemoteSignerConfig struct {
+	// Endpoint is the signing process URL, either http(s)://host/path or
+	// unix:///path/to/socket
+	Endpoint string `json:"endpoint"`
+
+	// KeyID selects the key when the signing process holds several
+	KeyID string `json:"key_id,omitempty"`
+
+	// Network is the network the signer's address is derived for
+	Network Network `json:"network"`
+
+	// Timeout is the request timeout duration
+	Timeout time.Duration `json:"timeout"`
+
+	// Headers are set on every request, for example to authenticate to the
+	// signing process
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// HTTPClient, if set, is used for http(s) endpoints, for example to
+	// present a TLS client certificate. Its Timeout takes precedence.
+	HTTPClient *http.Client `json:"-"`
+}
+
+// RemoteSigner is a Signer backed by a separate signing process.
+//
+// It speaks JSON-RPC 2.0 over HTTP or a Unix socket with two methods:
+//
+//	signer_getPublicKey  [{"key_id"}]             -> {"public_key": hex}
+//	signer_sign          [{"key_id", "message"}]  -> {"signature": hex}
+//
+// Messages and signatures are hex-encoded; messages are canonical
+// transaction encodings. Every signature returned by the signing process is
+// verified against its public key before use.
+type RemoteSigner struct {
+	rpcClient *RPCClient
+	keyID     string
+	publicKey string
+	address   string
+}
+
+// NewRemoteSigner connects to a signing process and fetches its public key
+func NewRemoteSigner(ctx context.Context, config *RemoteSignerConfig) (*RemoteSigner, error) {
+	if config == nil || config.Endpoint == "" {
+		return nil, fmt.Errorf("remote signer endpoint is required")
+	}
+
+	if config.Network == "" {
+		return nil, fmt.Errorf("remote signer network is required")
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	rpcClient, err := newSignerRPCClient(config, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &RemoteSigner{
+		rpcClient: rpcClient,
+		keyID:     config.KeyID,
+	}
+
+	var result struct {
+		PublicKey string `json:"public_key"`
+	}
+	params := map[string]interface{}{"key_id": signer.keyID}
+	if err := rpcClient.Call(ctx, "signer_getPublicKey", []interface{}{params}, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch public key from remote signer: %w", err)
+	}
+
+	address, err := GenerateAddress(result.PublicKey, config.Network)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid public key: %w", err)
+	}
+
+	signer.publicKey = result.PublicKey
+	signer.address = address
+	return signer, nil
+}
+
+// PublicKey returns the hex-encoded public key reported by the signing process
+func (s *RemoteSigner) PublicKey() string {
+	return s.publicKey
+}
+
+// Address returns the account address
+func (s *RemoteSigner) Address() string {
+	return s.address
+}
+
+// Sign asks the signing process to sign message
+func (s *RemoteSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	params := map[string]interface{}{
+		"key_id":  s.keyID,
+		"message": hex.EncodeToString(message),
+	}
+
+	var result struct {
+		Signature string `json:"signature"`
+	}
+	if err := s.rpcClient.Call(ctx, "signer_sign", []interface{}{params}, &result); err != nil {
+		return nil, fmt.Errorf("remote signer failed: %w", err)
+	}
+
+	valid, err := VerifySignature(s.publicKey, message, result.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid signature: %w", err)
+	}
+
+	if !valid {
+		return nil, fmt.Errorf("remote signer returned a signature that does not verify")
+	}
+
+	return hex.DecodeString(result.Signature)
+}
+
+// newSignerRPCClient creates an RPC client for the http(s) or unix endpoint
+// in config
+func newSignerRPCClient(config *RemoteSignerConfig, timeout time.Duration) (*RPCClient, error) {
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer endpoint: %w", err)
+	}
+
+	httpConfig := &ClientConfig{
+		Timeout:    timeout,
+		Headers:    config.Headers,
+		HTTPClient: config.HTTPClient,
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		rpcClient := NewRPCClient(config.Endpoint, timeout)
+		rpcClient.client = newHTTPClient(httpConfig)
+		return rpcClient, nil
+	case "unix":
+		socketPath := u.Path
+		if socketPath == "" {
+			socketPath = u.Opaque
+		}
+
+		httpConfig.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		}
+		rpcClient := NewRPCClient("http://unix/", timeout)
+		rpcClient.client = newHTTPClient(httpConfig)
+		return rpcClient, nil
+	default:
+		return nil, fmt.Errorf("unsupported remote signer scheme %q", u.Scheme)
+	}
+}
+
+// maxSignerRequestSize bounds the request bodies SignerHandler reads, in bytes
+const maxSignerRequestSize = 1 << 20
+
+// SignPolicy decides whether SignerHandler may sign tx with the key
+// selected by keyID. Returning an error refuses the request; the error
+// message is sent to the caller.
+type SignPolicy func(ctx context.Context, keyID string, tx *UnsignedTransaction) error
+
+// SignerHandler returns an http.Handler that serves the RemoteSigner
+// protocol for a set of signers keyed by key ID. It lets a signing process
+// built with this SDK expose keys that never leave it; the empty key ID
+// selects the default signer.
+//
+// The handler does no authentication: anyone who can reach it can have the
+// keys sign. Serve it on a unix socket with restricted permissions, or
+// behind middleware that authenticates callers.
+//
+// The handler only signs transactions: every signer_sign message must be a
+// canonical transaction encoding (see DecodeTransaction) sent from the
+// selected key's account, so callers cannot obtain signatures over
+// arbitrary bytes. If policy is not nil it is consulted for every such
+// transaction before signing, for example to limit recipients or amounts.
+func SignerHandler(signers map[string]Signer, policy SignPolicy) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request JSONRPCRequest
+		var params []struct {
+			KeyID   string `json:"key_id"`
+			Message string `json:"message"`
+		}
+		request.Params = &params
+
+		response := JSONRPCResponse{JSONRPC: "2.0"}
+		body := http.MaxBytesReader(w, r.Body, maxSignerRequestSize)
+		if err := json.NewDecoder(body).Decode(&request); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			response.Error = &JSONRPCError{Code: -32700, Message: "parse error"}
+			writeSignerResponse(w, response)
+			return
+		}
+		response.ID = request.ID
+
+		if len(params) != 1 {
+			response.Error = &JSONRPCError{Code: -32602, Message: "expected a single params object"}
+			writeSignerResponse(w, response)
+			return
+		}
+
+		keyID := params[0].KeyID
+		signer, ok := signers[keyID]
+		if !ok {
+			response.Error = &JSONRPCError{Code: -32602, Message: fmt.Sprintf("unknown key %q", keyID)}
+			writeSignerResponse(w, response)
+			return
+		}
+
+		switch request.Method {
+		case "signer_getPublicKey":
+			response.Result = map[string]string{"public_key": signer.PublicKey()}
+		case "signer_sign":
+			message, err := hex.DecodeString(params[0].Message)
+			if err != nil {
+				response.Error = &JSONRPCError{Code: -32602, Message: "invalid message hex"}
+				break
+			}
+
+			tx, err := DecodeTransaction(message)
+			if err != nil {
+				response.Error = &JSONRPCError{Code: -32602, Message: fmt.Sprintf("message is not a transaction: %v", err)}
+				break
+			}
+
+			if tx.From != signer.Address() {
+				response.Error = &JSONRPCError{Code: -32602, Message: fmt.Sprintf("transaction sender %s is not the account of key %q", tx.From, keyID)}
+				break
+			}
+
+			if policy != nil {
+				if err := policy(r.Context(), keyID, tx); err != nil {
+					response.Error = &JSONRPCError{Code: -32000, Message: fmt.Sprintf("transaction refused: %v", err)}
+					break
+				}
+			}
+
+			signature, err := signer.Sign(r.Context(), message)
+			if err != nil {
+				response.Error = &JSONRPCError{Code: -32000, Message: err.Error()}
+				break
+			}
+			response.Result = map[string]string{"signature": hex.EncodeToString(signature)}
+		default:
+			response.Error = &JSONRPCError{Code: -32601, Message: "method not found"}
+		}
+
+		writeSignerResponse(w, response)
+	})
+}
+
+func writeSignerResponse(w http.ResponseWriter, response JSONRPCResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
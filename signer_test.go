@@ -0,0 +1,167 @@
+package chert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRemoteSigner serves testPrivateKey with SignerHandler and connects
+// a RemoteSigner to it
+func newTestRemoteSigner(t *testing.T, policy SignPolicy) (*RemoteSigner, *httptest.Server) {
+	t.Helper()
+
+	local, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(SignerHandler(map[string]Signer{"": local}, policy))
+	t.Cleanup(server.Close)
+
+	remote, err := NewRemoteSigner(context.Background(), &RemoteSignerConfig{
+		Endpoint: server.URL,
+		Network:  NetworkMainnet,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, local.Address(), remote.Address())
+	return remote, server
+}
+
+func TestSignerHandlerSignsTransactions(t *testing.T) {
+	var seen []*UnsignedTransaction
+	remote, _ := newTestRemoteSigner(t, func(ctx context.Context, keyID string, tx *UnsignedTransaction) error {
+		seen = append(seen, tx)
+		return nil
+	})
+
+	unsigned := newTestTransfer(t)
+	signed, err := unsigned.SignWith(context.Background(), remote)
+	require.NoError(t, err)
+	require.NoError(t, signed.Verify())
+
+	require.Len(t, seen, 1)
+	assert.Equal(t, unsigned, seen[0])
+}
+
+func TestSignerHandlerRefusals(t *testing.T) {
+	remote, _ := newTestRemoteSigner(t, func(ctx context.Context, keyID string, tx *UnsignedTransaction) error {
+		if tx.Amount.Cmp(MustParseAmount("1000")) > 0 {
+			return fmt.Errorf("amount over limit")
+		}
+		return nil
+	})
+
+	other := newTestTransfer(t)
+	other.From = "chert1y8lrrhap2j3xzcntlp2qgm7jyudhhm2t94a7ec"
+	other.To = testMainnetAddress
+	otherEncoded, err := other.Encode()
+	require.NoError(t, err)
+
+	large := newTestTransfer(t)
+	large.Amount = MustParseAmount("5000")
+	largeEncoded, err := large.Encode()
+	require.NoError(t, err)
+
+	encoded, err := newTestTransfer(t).Encode()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		message []byte
+		wantErr string
+	}{
+		{"arbitrary bytes", []byte("hello"), "message is not a transaction"},
+		{"trailing bytes", append(append([]byte{}, encoded...), 0), "not canonical"},
+		{"another sender", otherEncoded, "is not the account of key"},
+		{"refused by policy", largeEncoded, "transaction refused: amount over limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := remote.Sign(context.Background(), tt.message)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSignerHandlerLimitsRequestSize(t *testing.T) {
+	_, server := newTestRemoteSigner(t, nil)
+
+	message := strings.Repeat("00", maxSignerRequestSize)
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"signer_sign","params":[{"message":%q}]}`, message)
+	resp, err := http.Post(server.URL, "application/json", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestRemoteSignerUnixSocket(t *testing.T) {
+	local, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	socketPath := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	userAgents := make(chan string, 1)
+	handler := SignerHandler(map[string]Signer{"": local}, nil)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case userAgents <- r.Header.Get("User-Agent"):
+		default:
+		}
+		handler.ServeHTTP(w, r)
+	})}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	remote, err := NewRemoteSigner(context.Background(), &RemoteSignerConfig{
+		Endpoint: "unix://" + socketPath,
+		Network:  NetworkMainnet,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, local.Address(), remote.Address())
+	assert.Equal(t, UserAgent, <-userAgents)
+}
+
+func TestRemoteSignerAuthentication(t *testing.T) {
+	local, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	handler := SignerHandler(map[string]Signer{"": local}, nil)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	config := &RemoteSignerConfig{
+		Endpoint:   server.URL,
+		Network:    NetworkMainnet,
+		HTTPClient: server.Client(),
+	}
+	_, err = NewRemoteSigner(context.Background(), config)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+
+	config.Headers = map[string]string{"Authorization": "Bearer s3cret"}
+	remote, err := NewRemoteSigner(context.Background(), config)
+	require.NoError(t, err)
+
+	signed, err := newTestTransfer(t).SignWith(context.Background(), remote)
+	require.NoError(t, err)
+	assert.NoError(t, signed.Verify())
+}
@@ -0,0 +1,128 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSimulationFailed is matched by every SimulationError
+var ErrSimulationFailed = errors.New("transaction simulation failed")
+
+// Simulation error codes reported by the node
+const (
+	SimErrInsufficientFunds = "insufficient_funds"
+	SimErrNonceTooLow       = "nonce_too_low"
+	SimErrFeeTooLow         = "fee_too_low"
+	SimErrInvalidSignature  = "invalid_signature"
+)
+
+// simulationErrors maps simulation error codes to the sentinel errors they match
+var simulationErrors = map[string]error{
+	SimErrInsufficientFunds: ErrInsufficientFunds,
+	SimErrNonceTooLow:       ErrNonceTooLow,
+	SimErrFeeTooLow:         ErrFeeTooLow,
+	SimErrInvalidSignature:  ErrInvalidSignature,
+}
+
+// SimulationResult is the predicted outcome of a transaction
+type SimulationResult struct {
+	Status        TransactionStatus `json:"status"`
+	Fee           Amount            `json:"fee"`
+	GasUsed       uint64            `json:"gas_used,omitempty"`
+	BalanceDeltas []BalanceDelta    `json:"balance_deltas"`
+	Errors        []SimulationError `json:"errors,omitempty"`
+}
+
+// BalanceDelta is the change a transaction would make to an account balance
+type BalanceDelta struct {
+	Address string `json:"address"`
+	Delta   Amount `json:"delta"`
+}
+
+// SimulationError is a reason a simulated transaction would fail. It matches
+// ErrSimulationFailed and, for known codes, a more specific sentinel such as
+// ErrInsufficientFunds.
+type SimulationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("simulation error %s: %s", e.Code, e.Message)
+}
+
+// Is reports whether e matches target
+func (e *SimulationError) Is(target error) bool {
+	if target == ErrSimulationFailed {
+		return true
+	}
+	sentinel, ok := simulationErrors[e.Code]
+	return ok && target == sentinel
+}
+
+// SimulateTransaction predicts the outcome of an unsigned transaction
+// without broadcasting it. The signature is not checked.
+//
+// If the node predicts a failure, the result is returned together with the
+// first SimulationError, so errors.Is(err, ErrInsufficientFunds) and similar
+// checks work.
+func (c *ChertClient) SimulateTransaction(ctx context.Context, tx *UnsignedTransaction) (*SimulationResult, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+
+	if err := tx.validate(); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+
+	return c.simulate(ctx, tx, nil)
+}
+
+// SimulateSignedTransaction predicts the outcome of a signed transaction,
+// including its signature check, without broadcasting it. Errors are
+// reported as for SimulateTransaction.
+func (c *ChertClient) SimulateSignedTransaction(ctx context.Context, signed *SignedTransaction) (*SimulationResult, error) {
+	if signed == nil {
+		return nil, fmt.Errorf("signed transaction is nil")
+	}
+
+	if err := signed.Verify(); err != nil {
+		return nil, err
+	}
+
+	return c.simulate(ctx, signed.Transaction, signed)
+}
+
+func (c *ChertClient) simulate(ctx context.Context, tx *UnsignedTransaction, signed *SignedTransaction) (*SimulationResult, error) {
+	if tx.NetworkID != c.networkID() {
+		return nil, fmt.Errorf("transaction is for network %s, client is on %s", tx.NetworkID, c.networkID())
+	}
+
+	route, params, err := transactionParams(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if signed != nil {
+		params["public_key"] = signed.PublicKey
+		params["signature"] = signed.Signature
+	}
+
+	request := map[string]interface{}{
+		"type":   tx.Type,
+		"method": route.method,
+		"params": params,
+	}
+
+	var result SimulationResult
+	if err := c.rpcClient.Call(ctx, "simulateTransaction", []interface{}{request}, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Errors) > 0 {
+		return &result, &result.Errors[0]
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,158 @@
+package chert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSimulationRequest is the request SimulateTransaction sends
+type testSimulationRequest struct {
+	Type   TransactionType        `json:"type"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// newTestSimulationServer answers simulateTransaction with result and
+// records the requests
+func newTestSimulationServer(t *testing.T, result map[string]interface{}) (*ChertClient, func() []testSimulationRequest) {
+	t.Helper()
+
+	requests := make(chan testSimulationRequest, 10)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		if req.Method != "simulateTransaction" {
+			req.fail(w, -32601, "method not found")
+			return
+		}
+
+		var request testSimulationRequest
+		req.param(t, &request)
+		requests <- request
+		req.reply(w, result)
+	})
+
+	return newTestClient(t, server, nil), func() []testSimulationRequest {
+		var got []testSimulationRequest
+		for {
+			select {
+			case request := <-requests:
+				got = append(got, request)
+			default:
+				return got
+			}
+		}
+	}
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	client, requests := newTestSimulationServer(t, map[string]interface{}{
+		"status":   TxStatusConfirmed,
+		"fee":      "0.1",
+		"gas_used": 21000,
+		"balance_deltas": []map[string]string{
+			{"address": testMainnetAddress, "delta": "-100.1"},
+			{"address": testValidatorAddress, "delta": "100"},
+		},
+	})
+
+	tx := newTestTransfer(t)
+	result, err := client.SimulateTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, TxStatusConfirmed, result.Status)
+	assert.Equal(t, "0.1", result.Fee.String())
+	assert.EqualValues(t, 21000, result.GasUsed)
+	require.Len(t, result.BalanceDeltas, 2)
+	assert.Equal(t, "-100.1", result.BalanceDeltas[0].Delta.String())
+
+	sent := requests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, TxTypeTransfer, sent[0].Type)
+	assert.Equal(t, "sendTransaction", sent[0].Method)
+	assert.Equal(t, tx.To, sent[0].Params["recipient"])
+	assert.EqualValues(t, tx.Nonce, sent[0].Params["nonce"])
+	assert.NotContains(t, sent[0].Params, "signature")
+
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+	signed, err := tx.SignWith(context.Background(), signer)
+	require.NoError(t, err)
+
+	_, err = client.SimulateSignedTransaction(context.Background(), signed)
+	require.NoError(t, err)
+	sent = requests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, signed.Signature, sent[0].Params["signature"])
+	assert.Equal(t, signed.PublicKey, sent[0].Params["public_key"])
+}
+
+func TestSimulateTransactionErrors(t *testing.T) {
+	tests := []struct {
+		code string
+		want error
+	}{
+		{code: SimErrInsufficientFunds, want: ErrInsufficientFunds},
+		{code: SimErrNonceTooLow, want: ErrNonceTooLow},
+		{code: SimErrFeeTooLow, want: ErrFeeTooLow},
+		{code: SimErrInvalidSignature, want: ErrInvalidSignature},
+		{code: "out_of_gas"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			client, _ := newTestSimulationServer(t, map[string]interface{}{
+				"status": TxStatusFailed,
+				"fee":    "0.1",
+				"errors": []map[string]string{
+					{"code": tt.code, "message": "would fail"},
+					{"code": SimErrNonceTooLow, "message": "also fails"},
+				},
+			})
+
+			result, err := client.SimulateTransaction(context.Background(), newTestTransfer(t))
+			require.NotNil(t, result, "the result comes with the error")
+			assert.Equal(t, TxStatusFailed, result.Status)
+			assert.Len(t, result.Errors, 2)
+
+			var simErr *SimulationError
+			require.ErrorAs(t, err, &simErr)
+			assert.Equal(t, tt.code, simErr.Code, "the first error is returned")
+			assert.ErrorIs(t, err, ErrSimulationFailed)
+			for _, sentinel := range testSentinels {
+				assert.Equal(t, sentinel == tt.want, errors.Is(err, sentinel), sentinel)
+			}
+		})
+	}
+}
+
+func TestSimulateTransactionChecksLocally(t *testing.T) {
+	var calls int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		atomic.AddInt32(&calls, 1)
+		req.reply(w, map[string]interface{}{"status": TxStatusConfirmed})
+	})
+	client := newTestClient(t, server, nil)
+
+	_, err := client.SimulateTransaction(context.Background(), nil)
+	assert.Error(t, err)
+
+	testnet := newTestTransfer(t)
+	testnet.NetworkID = string(NetworkTestnet)
+	_, err = client.SimulateTransaction(context.Background(), testnet)
+	assert.ErrorContains(t, err, "testnet")
+
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+	signed, err := newTestTransfer(t).SignWith(context.Background(), signer)
+	require.NoError(t, err)
+	signed.Signature = flipHexDigit(signed.Signature)
+
+	_, err = client.SimulateSignedTransaction(context.Background(), signed)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	assert.Zero(t, atomic.LoadInt32(&calls), "nothing is sent")
+}
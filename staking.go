@@ -6,6 +6,11 @@ import (
 )
 
 // StakingManager handles staking and delegation operations
+//
+// Methods that send a transaction sign it with the nonce GetNonce reports
+// for the signer. To have several transactions from one account in flight
+// at once, build them with the Build methods and send them through a
+// NonceManager instead.
 type StakingManager struct {
 	client *ChertClient
 }
@@ -32,47 +37,69 @@ func (sm *StakingManager) GetValidator(ctx context.Context, address string) (*Va
 }
 
 // Delegate delegates tokens to a validator
-func (sm *StakingManager) Delegate(ctx context.Context, delegatorAddress, validatorAddress, amount, fee string) (string, error) {
-	params := map[string]interface{}{
-		"delegator": delegatorAddress,
-		"validator": validatorAddress,
-		"amount":    amount,
-		"fee":       fee,
+func (sm *StakingManager) Delegate(ctx context.Context, signer Signer, validatorAddress string, amount, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := sm.client.rpcClient.Call(ctx, "staking_delegate", []interface{}{params}, &result)
+	nonce, err := sm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := sm.BuildDelegate(signer.Address(), validatorAddress, amount, fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid delegation response")
+	return sm.client.signAndBroadcast(ctx, tx, signer)
 }
 
 // Undelegate removes delegation from a validator
-func (sm *StakingManager) Undelegate(ctx context.Context, delegatorAddress, validatorAddress, amount, fee string) (string, error) {
-	params := map[string]interface{}{
-		"delegator": delegatorAddress,
-		"validator": validatorAddress,
-		"amount":    amount,
-		"fee":       fee,
+func (sm *StakingManager) Undelegate(ctx context.Context, signer Signer, validatorAddress string, amount, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := sm.client.rpcClient.Call(ctx, "staking_undelegate", []interface{}{params}, &result)
+	nonce, err := sm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := sm.BuildUndelegate(signer.Address(), validatorAddress, amount, fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid undelegation response")
+	return sm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildDelegate builds an unsigned delegation with the given nonce for offline signing
+func (sm *StakingManager) BuildDelegate(delegatorAddress, validatorAddress string, amount, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:   TxEncodingVersion,
+		Type:      TxTypeDelegate,
+		NetworkID: sm.client.networkID(),
+		From:      delegatorAddress,
+		To:        validatorAddress,
+		Amount:    amount,
+		Fee:       fee,
+		Nonce:     nonce,
+	})
+}
+
+// BuildUndelegate builds an unsigned undelegation with the given nonce for offline signing
+func (sm *StakingManager) BuildUndelegate(delegatorAddress, validatorAddress string, amount, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:   TxEncodingVersion,
+		Type:      TxTypeUndelegate,
+		NetworkID: sm.client.networkID(),
+		From:      delegatorAddress,
+		To:        validatorAddress,
+		Amount:    amount,
+		Fee:       fee,
+		Nonce:     nonce,
+	})
 }
 
 // GetDelegations retrieves delegations for an account
@@ -92,65 +119,106 @@ func (sm *StakingManager) GetStakingRewards(ctx context.Context, delegatorAddres
 }
 
 // ClaimRewards claims staking rewards
-func (sm *StakingManager) ClaimRewards(ctx context.Context, delegatorAddress string, validatorAddress, fee string) (string, error) {
-	params := map[string]interface{}{
-		"delegator": delegatorAddress,
-		"validator": validatorAddress,
-		"fee":       fee,
+func (sm *StakingManager) ClaimRewards(ctx context.Context, signer Signer, validatorAddress string, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := sm.client.rpcClient.Call(ctx, "staking_claimRewards", []interface{}{params}, &result)
+	nonce, err := sm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := sm.BuildClaimRewards(signer.Address(), validatorAddress, fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid claim rewards response")
+	return sm.client.signAndBroadcast(ctx, tx, signer)
 }
 
-// RegisterValidator registers a new validator
-func (sm *StakingManager) RegisterValidator(ctx context.Context, validator *Validator, ownerAddress, fee string) (string, error) {
-	params := map[string]interface{}{
-		"validator":     validator,
-		"owner_address": ownerAddress,
-		"fee":           fee,
+// BuildClaimRewards builds an unsigned rewards claim with the given nonce for offline signing
+func (sm *StakingManager) BuildClaimRewards(delegatorAddress, validatorAddress string, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:   TxEncodingVersion,
+		Type:      TxTypeClaimRewards,
+		NetworkID: sm.client.networkID(),
+		From:      delegatorAddress,
+		To:        validatorAddress,
+		Fee:       fee,
+		Nonce:     nonce,
+	})
+}
+
+// RegisterValidator registers a new validator owned by the signer
+func (sm *StakingManager) RegisterValidator(ctx context.Context, signer Signer, validator *Validator, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := sm.client.rpcClient.Call(ctx, "staking_registerValidator", []interface{}{params}, &result)
+	nonce, err := sm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := sm.BuildRegisterValidator(validator, signer.Address(), fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid validator registration response")
+	return sm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildRegisterValidator builds an unsigned validator registration with the given nonce for offline signing
+func (sm *StakingManager) BuildRegisterValidator(validator *Validator, ownerAddress string, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	if validator == nil {
+		return nil, fmt.Errorf("validator is nil")
+	}
+
+	return checkUnsigned(&UnsignedTransaction{
+		Version:            TxEncodingVersion,
+		Type:               TxTypeRegisterValidator,
+		NetworkID:          sm.client.networkID(),
+		From:               ownerAddress,
+		To:                 validator.Address,
+		Fee:                fee,
+		Nonce:              nonce,
+		ValidatorName:      validator.Name,
+		ValidatorPublicKey: validator.PublicKey,
+		CommissionRate:     validator.CommissionRate,
+		StakeAmount:        validator.StakeAmount,
+	})
 }
 
 // UpdateCommission updates a validator's commission rate
-func (sm *StakingManager) UpdateCommission(ctx context.Context, validatorAddress, ownerAddress string, newRate uint32, fee string) (string, error) {
-	params := map[string]interface{}{
-		"validator_address": validatorAddress,
-		"owner_address":     ownerAddress,
-		"new_rate":          newRate,
-		"fee":               fee,
+func (sm *StakingManager) UpdateCommission(ctx context.Context, signer Signer, validatorAddress string, newRate uint32, fee Amount) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	var result map[string]interface{}
-	err := sm.client.rpcClient.Call(ctx, "staking_updateCommission", []interface{}{params}, &result)
+	nonce, err := sm.client.Wallet.GetNonce(ctx, signer.Address())
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to fetch nonce for %s: %w", signer.Address(), err)
 	}
 
-	if txHash, ok := result["tx_hash"].(string); ok {
-		return txHash, nil
+	tx, err := sm.BuildUpdateCommission(validatorAddress, signer.Address(), newRate, fee, nonce)
+	if err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("invalid commission update response")
-}
\ No newline at end of file
+	return sm.client.signAndBroadcast(ctx, tx, signer)
+}
+
+// BuildUpdateCommission builds an unsigned commission update with the given nonce for offline signing
+func (sm *StakingManager) BuildUpdateCommission(validatorAddress, ownerAddress string, newRate uint32, fee Amount, nonce uint64) (*UnsignedTransaction, error) {
+	return checkUnsigned(&UnsignedTransaction{
+		Version:        TxEncodingVersion,
+		Type:           TxTypeUpdateCommission,
+		NetworkID:      sm.client.networkID(),
+		From:           ownerAddress,
+		To:             validatorAddress,
+		Fee:            fee,
+		Nonce:          nonce,
+		CommissionRate: newRate,
+	})
+}
@@ -0,0 +1,76 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testValidatorAddress = "chert1y8lrrhap2j3xzcntlp2qgm7jyudhhm2t94a7ec"
+
+// newTestNonceServer starts a node that expects nonces in sequence from
+// first and answers sends on method with the hash returned by hash
+func newTestNonceServer(t *testing.T, first uint64, method string, hash func(nonce uint64) string) (*httptest.Server, *[]uint64) {
+	t.Helper()
+
+	var (
+		mu   sync.Mutex
+		next = first
+		sent []uint64
+	)
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req.Method {
+		case "getNonce":
+			req.reply(w, next)
+		case method:
+			var params struct {
+				Nonce uint64 `json:"nonce"`
+			}
+			req.param(t, &params)
+			sent = append(sent, params.Nonce)
+			if params.Nonce != next {
+				req.fail(w, -32000, "nonce too low")
+				return
+			}
+			next++
+			req.reply(w, map[string]string{"tx_hash": hash(params.Nonce)})
+		default:
+			req.fail(w, -32601, "method not found")
+		}
+	})
+	return server, &sent
+}
+
+func TestDelegateUsesNextNonce(t *testing.T) {
+	signer, err := NewLocalSigner(testPrivateKey, NetworkMainnet)
+	require.NoError(t, err)
+
+	amount := MustParseAmount("10")
+	fee := MustParseAmount("0.01")
+
+	var client *ChertClient
+	server, sent := newTestNonceServer(t, 3, "staking_delegate", func(nonce uint64) string {
+		tx, err := client.Staking.BuildDelegate(signer.Address(), testValidatorAddress, amount, fee, nonce)
+		require.NoError(t, err)
+		hash, err := tx.Hash()
+		require.NoError(t, err)
+		return hash
+	})
+	client = newTestClient(t, server, nil)
+
+	first, err := client.Staking.Delegate(context.Background(), signer, testValidatorAddress, amount, fee)
+	require.NoError(t, err)
+	second, err := client.Staking.Delegate(context.Background(), signer, testValidatorAddress, amount, fee)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, []uint64{3, 4}, *sent)
+}
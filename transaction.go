@@ -0,0 +1,395 @@
+package chert
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// UnsignedTransaction is a fully specified transaction that has not been
+// signed yet. It can be serialized as JSON, moved to an offline host and
+// signed there with Sign.
+type UnsignedTransaction struct {
+	Version   byte            `json:"version"`
+	Type      TransactionType `json:"type"`
+	NetworkID string          `json:"network_id"`
+	From      string          `json:"from"`
+
+	// To is the recipient of a transfer or the validator of a staking operation
+	To     string `json:"to,omitempty"`
+	Amount Amount `json:"amount"`
+	Fee    Amount `json:"fee"`
+	Nonce  uint64 `json:"nonce"`
+	Memo   string `json:"memo,omitempty"`
+
+	// Governance fields
+	ProposalID  string     `json:"proposal_id,omitempty"`
+	Option      VoteOption `json:"option,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+
+	// Validator fields
+	ValidatorName      string `json:"validator_name,omitempty"`
+	ValidatorPublicKey string `json:"validator_public_key,omitempty"`
+	CommissionRate     uint32 `json:"commission_rate,omitempty"`
+	StakeAmount        uint64 `json:"stake_amount,omitempty"`
+}
+
+// SignedTransaction is an UnsignedTransaction together with the signer's
+// public key and the Ed25519 signature over its canonical encoding
+type SignedTransaction struct {
+	Transaction *UnsignedTransaction `json:"transaction"`
+	PublicKey   string               `json:"public_key"`
+	Signature   string               `json:"signature"`
+	Hash        string               `json:"hash"`
+}
+
+// newTransferTransaction builds an unsigned transfer from a transaction request
+func newTransferTransaction(networkID, sender string, request *TransactionRequest) *UnsignedTransaction {
+	return &UnsignedTransaction{
+		Version:   TxEncodingVersion,
+		Type:      TxTypeTransfer,
+		NetworkID: networkID,
+		From:      sender,
+		To:        request.To,
+		Amount:    request.Amount,
+		Fee:       request.Fee,
+		Nonce:     request.Nonce,
+		Memo:      request.Memo,
+	}
+}
+
+// checkUnsigned validates a freshly built transaction
+func checkUnsigned(tx *UnsignedTransaction) (*UnsignedTransaction, error) {
+	if err := tx.validate(); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// ParseUnsignedTransaction decodes and validates a serialized unsigned transaction
+func ParseUnsignedTransaction(data []byte) (*UnsignedTransaction, error) {
+	var tx UnsignedTransaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to decode unsigned transaction: %w", err)
+	}
+
+	if err := tx.validate(); err != nil {
+		return nil, fmt.Errorf("invalid unsigned transaction: %w", err)
+	}
+
+	return &tx, nil
+}
+
+// ParseSignedTransaction decodes a serialized signed transaction and
+// verifies its hash and signature
+func ParseSignedTransaction(data []byte) (*SignedTransaction, error) {
+	var tx SignedTransaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	if err := tx.Verify(); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// Sign signs the transaction with a hex-encoded Ed25519 private key. The key
+// must belong to the transaction's sender. Sign needs no network access.
+func (tx *UnsignedTransaction) Sign(privateKey string) (*SignedTransaction, error) {
+	signer, err := NewLocalSigner(privateKey, Network(tx.NetworkID))
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.SignWith(context.Background(), signer)
+}
+
+// SignWith signs the transaction with a Signer belonging to its sender
+func (tx *UnsignedTransaction) SignWith(ctx context.Context, signer Signer) (*SignedTransaction, error) {
+	if err := requireSigner(signer); err != nil {
+		return nil, err
+	}
+
+	encoded, err := tx.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	if signer.Address() != tx.From {
+		return nil, fmt.Errorf("signer belongs to %s, not sender %s", signer.Address(), tx.From)
+	}
+
+	signature, err := signer.Sign(ctx, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return &SignedTransaction{
+		Transaction: tx,
+		PublicKey:   signer.PublicKey(),
+		Signature:   hex.EncodeToString(signature),
+		Hash:        hashEncoding(encoded),
+	}, nil
+}
+
+// Verify checks that the signature is valid for the transaction, that the
+// public key belongs to the sender and that the hash matches the encoding.
+// A signature that does not verify fails with ErrInvalidSignature.
+func (tx *SignedTransaction) Verify() error {
+	if tx == nil {
+		return fmt.Errorf("signed transaction is nil")
+	}
+
+	if tx.Transaction == nil {
+		return fmt.Errorf("signed transaction is missing its transaction")
+	}
+
+	encoded, err := tx.Transaction.Encode()
+	if err != nil {
+		return fmt.Errorf("invalid transaction: %w", err)
+	}
+
+	if hash := hashEncoding(encoded); tx.Hash != hash {
+		return fmt.Errorf("transaction hash mismatch: got %s, expected %s", tx.Hash, hash)
+	}
+
+	address, err := GenerateAddress(tx.PublicKey, Network(tx.Transaction.NetworkID))
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	if address != tx.Transaction.From {
+		return fmt.Errorf("public key belongs to %s, not sender %s", address, tx.Transaction.From)
+	}
+
+	valid, err := VerifySignature(tx.PublicKey, encoded, tx.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: verification failed", ErrInvalidSignature)
+	}
+
+	return nil
+}
+
+// broadcastRoute describes how a signed transaction of a given type is submitted
+type broadcastRoute struct {
+	method    string
+	resultKey string
+	params    func(tx *UnsignedTransaction) map[string]interface{}
+}
+
+var broadcastRoutes = map[TransactionType]broadcastRoute{
+	TxTypeTransfer: {
+		method:    "sendTransaction",
+		resultKey: "hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"sender":    tx.From,
+				"recipient": tx.To,
+				"amount":    tx.Amount,
+				"fee":       tx.Fee,
+			}
+		},
+	},
+	TxTypeDelegate: {
+		method:    "staking_delegate",
+		resultKey: "tx_hash",
+		params:    stakingParams,
+	},
+	TxTypeUndelegate: {
+		method:    "staking_undelegate",
+		resultKey: "tx_hash",
+		params:    stakingParams,
+	},
+	TxTypeClaimRewards: {
+		method:    "staking_claimRewards",
+		resultKey: "tx_hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"delegator": tx.From,
+				"validator": tx.To,
+				"fee":       tx.Fee,
+			}
+		},
+	},
+	TxTypeVote: {
+		method:    "governance_vote",
+		resultKey: "tx_hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"proposal_id": tx.ProposalID,
+				"voter":       tx.From,
+				"option":      tx.Option,
+				"fee":         tx.Fee,
+			}
+		},
+	},
+	TxTypeCreateProposal: {
+		method:    "governance_createProposal",
+		resultKey: "proposal_id",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"title":       tx.Title,
+				"description": tx.Description,
+				"proposer":    tx.From,
+				"fee":         tx.Fee,
+			}
+		},
+	},
+	TxTypeRegisterValidator: {
+		method:    "staking_registerValidator",
+		resultKey: "tx_hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"validator": map[string]interface{}{
+					"address":         tx.To,
+					"name":            tx.ValidatorName,
+					"public_key":      tx.ValidatorPublicKey,
+					"commission_rate": tx.CommissionRate,
+					"stake_amount":    tx.StakeAmount,
+				},
+				"owner_address": tx.From,
+				"fee":           tx.Fee,
+			}
+		},
+	},
+	TxTypeUpdateCommission: {
+		method:    "staking_updateCommission",
+		resultKey: "tx_hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"validator_address": tx.To,
+				"owner_address":     tx.From,
+				"new_rate":          tx.CommissionRate,
+				"fee":               tx.Fee,
+			}
+		},
+	},
+	TxTypeExecuteProposal: {
+		method:    "governance_executeProposal",
+		resultKey: "tx_hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"proposal_id": tx.ProposalID,
+				"executor":    tx.From,
+				"fee":         tx.Fee,
+			}
+		},
+	},
+	TxTypeCancelProposal: {
+		method:    "governance_cancelProposal",
+		resultKey: "tx_hash",
+		params: func(tx *UnsignedTransaction) map[string]interface{} {
+			return map[string]interface{}{
+				"proposal_id": tx.ProposalID,
+				"proposer":    tx.From,
+				"fee":         tx.Fee,
+			}
+		},
+	},
+}
+
+func stakingParams(tx *UnsignedTransaction) map[string]interface{} {
+	return map[string]interface{}{
+		"delegator": tx.From,
+		"validator": tx.To,
+		"amount":    tx.Amount,
+		"fee":       tx.Fee,
+	}
+}
+
+// BroadcastSignedTransaction verifies a signed transaction and submits it to
+// the network. It returns the transaction hash, or the new proposal ID for
+// proposal creation.
+func (c *ChertClient) BroadcastSignedTransaction(ctx context.Context, signed *SignedTransaction) (string, error) {
+	if err := c.checkBroadcast(signed); err != nil {
+		return "", err
+	}
+
+	route, params, err := transactionParams(signed.Transaction)
+	if err != nil {
+		return "", err
+	}
+	params["public_key"] = signed.PublicKey
+	params["signature"] = signed.Signature
+
+	var result map[string]interface{}
+	requestID, err := c.rpcClient.callWithID(ctx, route.method, []interface{}{params}, &result)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := result[route.resultKey].(string)
+	if !ok {
+		return "", invalidResponse(route.method, requestID, "missing %s", route.resultKey)
+	}
+
+	if route.resultKey != "proposal_id" && value != signed.Hash {
+		return "", invalidResponse(route.method, requestID, "node reported transaction hash %s, expected %s", value, signed.Hash)
+	}
+
+	return value, nil
+}
+
+// checkBroadcast makes the checks BroadcastSignedTransaction does before
+// sending anything
+func (c *ChertClient) checkBroadcast(signed *SignedTransaction) error {
+	if signed == nil {
+		return fmt.Errorf("signed transaction is nil")
+	}
+
+	if err := signed.Verify(); err != nil {
+		return err
+	}
+
+	if tx := signed.Transaction; tx.NetworkID != c.networkID() {
+		return fmt.Errorf("transaction is for network %s, client is on %s", tx.NetworkID, c.networkID())
+	}
+
+	return nil
+}
+
+// transactionParams returns the route for tx and its RPC parameters,
+// without the signature
+func transactionParams(tx *UnsignedTransaction) (broadcastRoute, map[string]interface{}, error) {
+	route, ok := broadcastRoutes[tx.Type]
+	if !ok {
+		return broadcastRoute{}, nil, fmt.Errorf("unknown transaction type %d", byte(tx.Type))
+	}
+
+	params := route.params(tx)
+	params["version"] = tx.Version
+	params["network_id"] = tx.NetworkID
+	params["nonce"] = tx.Nonce
+
+	if tx.Memo != "" {
+		params["memo"] = tx.Memo
+	}
+
+	return route, params, nil
+}
+
+// signAndBroadcast signs a freshly built transaction and submits it
+func (c *ChertClient) signAndBroadcast(ctx context.Context, tx *UnsignedTransaction, signer Signer) (string, error) {
+	signed, err := tx.SignWith(ctx, signer)
+	if err != nil {
+		return "", err
+	}
+
+	return c.BroadcastSignedTransaction(ctx, signed)
+}
+
+// requireSigner rejects a nil signer before it is dereferenced
+func requireSigner(signer Signer) error {
+	if signer == nil {
+		return fmt.Errorf("signer is required")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package chert
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPrivateKey = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// newTestTransfer builds a mainnet transfer from testPrivateKey's account
+func newTestTransfer(t *testing.T) *UnsignedTransaction {
+	t.Helper()
+
+	tx, err := checkUnsigned(newTransferTransaction("mainnet", testMainnetAddress, &TransactionRequest{
+		To:     "chert1y8lrrhap2j3xzcntlp2qgm7jyudhhm2t94a7ec",
+		Amount: MustParseAmount("100"),
+		Fee:    MustParseAmount("0.1"),
+		Nonce:  7,
+	}))
+	require.NoError(t, err)
+	return tx
+}
+
+func TestSignedTransactionVerify(t *testing.T) {
+	signed, err := newTestTransfer(t).Sign(testPrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, signed.Verify())
+
+	data, err := json.Marshal(signed)
+	require.NoError(t, err)
+	parsed, err := ParseSignedTransaction(data)
+	require.NoError(t, err)
+	assert.Equal(t, signed, parsed)
+
+	other, err := NewLocalSigner("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60", NetworkMainnet)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(tx *SignedTransaction)
+	}{
+		{"changed amount", func(tx *SignedTransaction) { tx.Transaction.Amount = MustParseAmount("1000") }},
+		{"changed hash", func(tx *SignedTransaction) { tx.Hash = flipHexDigit(tx.Hash) }},
+		{"changed signature", func(tx *SignedTransaction) { tx.Signature = flipHexDigit(tx.Signature) }},
+		{"public key of another account", func(tx *SignedTransaction) { tx.PublicKey = other.PublicKey() }},
+		{"missing transaction", func(tx *SignedTransaction) { tx.Transaction = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := *signed
+			unsigned := *signed.Transaction
+			modified.Transaction = &unsigned
+			tt.modify(&modified)
+			assert.Error(t, modified.Verify())
+		})
+	}
+
+	var nilTx *SignedTransaction
+	assert.Error(t, nilTx.Verify())
+}
+
+func TestSignRejectsOtherSender(t *testing.T) {
+	_, err := newTestTransfer(t).Sign("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	assert.Error(t, err)
+}
+
+func TestBroadcastChecksBeforeSending(t *testing.T) {
+	// Nothing listens on the endpoint; every case must fail before a request
+	client, err := NewClient(&ClientConfig{Endpoint: "http://127.0.0.1:1", Network: NetworkTestnet})
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.BroadcastSignedTransaction(context.Background(), nil)
+	assert.EqualError(t, err, "signed transaction is nil")
+
+	_, err = client.SimulateSignedTransaction(context.Background(), nil)
+	assert.EqualError(t, err, "signed transaction is nil")
+
+	signed, err := newTestTransfer(t).Sign(testPrivateKey)
+	require.NoError(t, err)
+	_, err = client.BroadcastSignedTransaction(context.Background(), signed)
+	assert.EqualError(t, err, "transaction is for network mainnet, client is on testnet")
+}
@@ -0,0 +1,87 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+)
+
+// UserAgent is sent with every request
+const UserAgent = "chert-sdk-go/" + SDKVersion
+
+// headerTransport sets the SDK's headers on every request before passing
+// it to the underlying transport. Headers already set on the request, such
+// as those from WithRequestHeader, take precedence.
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, values := range t.header {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+type requestHeaderKey struct{}
+
+// WithRequestHeader returns a context under which RPC requests carry the
+// header key: value, in addition to the client's headers. Interceptors can
+// use it for per-tenant headers or refreshed auth tokens.
+func WithRequestHeader(ctx context.Context, key, value string) context.Context {
+	header := make(http.Header)
+	if parent, ok := ctx.Value(requestHeaderKey{}).(http.Header); ok {
+		header = parent.Clone()
+	}
+	header.Set(key, value)
+	return context.WithValue(ctx, requestHeaderKey{}, header)
+}
+
+// setRequestHeaders adds the headers set with WithRequestHeader to req
+func setRequestHeaders(ctx context.Context, req *http.Request) {
+	header, ok := ctx.Value(requestHeaderKey{}).(http.Header)
+	if !ok {
+		return
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+}
+
+// newHTTPClient returns the client all RPC traffic goes through. It starts
+// from config.HTTPClient or config.Transport, if set, and adds the
+// User-Agent, the API key and the custom headers to every request.
+func newHTTPClient(config *ClientConfig) *http.Client {
+	var client http.Client
+	if config.HTTPClient != nil {
+		client = *config.HTTPClient
+	}
+	if config.Transport != nil {
+		client.Transport = config.Transport
+	}
+	if client.Transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+	if client.Timeout == 0 {
+		client.Timeout = config.Timeout
+	}
+
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+	header.Set("User-Agent", UserAgent)
+
+	if config.APIKey != "" {
+		header.Set("Authorization", "Bearer "+config.APIKey)
+	}
+
+	for key, value := range config.Headers {
+		header.Set(key, value)
+	}
+
+	client.Transport = &headerTransport{base: client.Transport, header: header}
+	return &client
+}
@@ -14,16 +14,16 @@ type Account struct {
 
 // Balance represents an account balance
 type Balance struct {
-	Available string `json:"available"`
-	Pending   string `json:"pending"`
-	Total     string `json:"total"`
+	Available Amount `json:"available"`
+	Pending   Amount `json:"pending"`
+	Total     Amount `json:"total"`
 }
 
 // TransactionRequest represents a transaction request
 type TransactionRequest struct {
 	To     string `json:"to"`
-	Amount string `json:"amount"`
-	Fee    string `json:"fee"`
+	Amount Amount `json:"amount"`
+	Fee    Amount `json:"fee"`
 	Memo   string `json:"memo,omitempty"`
 	Nonce  uint64 `json:"nonce,omitempty"`
 }
@@ -33,13 +33,30 @@ type Transaction struct {
 	Hash        string    `json:"hash"`
 	From        string    `json:"from"`
 	To          string    `json:"to"`
-	Amount      string    `json:"amount"`
-	Fee         string    `json:"fee"`
+	Amount      Amount    `json:"amount"`
+	Fee         Amount    `json:"fee"`
 	Memo        string    `json:"memo,omitempty"`
 	BlockHeight uint64    `json:"block_height,omitempty"`
 	Status      string    `json:"status"`
 	Timestamp   time.Time `json:"timestamp"`
 	Nonce       uint64    `json:"nonce"`
+	NetworkID   string    `json:"network_id,omitempty"`
+
+	// Type is the operation of the transaction; transactions without one
+	// are transfers
+	Type TransactionType `json:"type,omitempty"`
+
+	// Governance fields
+	ProposalID  string     `json:"proposal_id,omitempty"`
+	Option      VoteOption `json:"option,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+
+	// Validator fields
+	ValidatorName      string `json:"validator_name,omitempty"`
+	ValidatorPublicKey string `json:"validator_public_key,omitempty"`
+	CommissionRate     uint32 `json:"commission_rate,omitempty"`
+	StakeAmount        uint64 `json:"stake_amount,omitempty"`
 }
 
 // TransactionStatus represents the status of a transaction
@@ -80,8 +97,8 @@ const (
 type PrivateTransactionRequest struct {
 	SenderKeys    StealthKeys  `json:"sender_keys"`
 	RecipientViewKey string    `json:"recipient_view_key"`
-	Amount        string       `json:"amount"`
-	Fee           string       `json:"fee"`
+	Amount        Amount       `json:"amount"`
+	Fee           Amount       `json:"fee"`
 	Memo          string       `json:"memo,omitempty"`
 	PrivacyLevel  PrivacyLevel `json:"privacy_level"`
 	Nonce         uint64       `json:"nonce"`
@@ -89,21 +106,21 @@ type PrivateTransactionRequest struct {
 
 type PrivateTransaction struct {
 	TxID      string    `json:"tx_id"`
-	Amount    string    `json:"amount"`
+	Amount    Amount    `json:"amount"`
 	Memo      string    `json:"memo,omitempty"`
 	Sender    string    `json:"sender,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
-	Fee       string    `json:"fee"`
+	Fee       Amount    `json:"fee"`
 }
 
 // Staking types
 type Validator struct {
 	Address         string `json:"address"`
 	Name            string `json:"name"`
-	VotingPower     string `json:"voting_power"`
+	VotingPower     Amount `json:"voting_power"`
 	Commission      string `json:"commission"`
 	Status          string `json:"status"`
-	TotalDelegated  string `json:"total_delegated"`
+	TotalDelegated  Amount `json:"total_delegated"`
 	DelegatorCount  uint64 `json:"delegator_count"`
 	PublicKey       string `json:"public_key,omitempty"`
 	StakeAmount     uint64 `json:"stake_amount,omitempty"`
@@ -123,21 +140,21 @@ const (
 
 type DelegationRequest struct {
 	ValidatorAddress string `json:"validator_address"`
-	Amount           string `json:"amount"`
-	Fee              string `json:"fee"`
+	Amount           Amount `json:"amount"`
+	Fee              Amount `json:"fee"`
 }
 
 type Delegation struct {
 	ValidatorAddress string    `json:"validator_address"`
-	Amount           string    `json:"amount"`
-	Rewards          string    `json:"rewards"`
+	Amount           Amount    `json:"amount"`
+	Rewards          Amount    `json:"rewards"`
 	Timestamp        time.Time `json:"timestamp"`
 }
 
 type StakingRewards struct {
-	Total    string     `json:"total"`
-	Available string    `json:"available"`
-	Pending   string     `json:"pending"`
+	Total    Amount     `json:"total"`
+	Available Amount    `json:"available"`
+	Pending   Amount     `json:"pending"`
 	LastClaim *time.Time `json:"last_claim,omitempty"`
 }
 
@@ -164,10 +181,10 @@ const (
 )
 
 type VoteTally struct {
-	Yes        string `json:"yes"`
-	No         string `json:"no"`
-	Abstain    string `json:"abstain"`
-	NoWithVeto string `json:"no_with_veto"`
+	Yes        Amount `json:"yes"`
+	No         Amount `json:"no"`
+	Abstain    Amount `json:"abstain"`
+	NoWithVeto Amount `json:"no_with_veto"`
 }
 
 type VoteOption string
@@ -182,7 +199,7 @@ const (
 type VoteRequest struct {
 	ProposalID string    `json:"proposal_id"`
 	Option     VoteOption `json:"option"`
-	Fee        string     `json:"fee"`
+	Fee        Amount     `json:"fee"`
 }
 
 // Network types
@@ -207,9 +224,9 @@ type Block struct {
 
 // Fee estimation
 type Fee struct {
-	Amount    string `json:"amount"`
+	Amount    Amount `json:"amount"`
 	GasLimit  uint64 `json:"gas_limit,omitempty"`
-	GasPrice  string `json:"gas_price,omitempty"`
+	GasPrice  Amount `json:"gas_price,omitempty"`
 }
 
 // JSON-RPC types
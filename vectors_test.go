@@ -0,0 +1,177 @@
+package chert
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVectors mirrors testdata/vectors.json, which other implementations
+// check themselves against
+type testVectors struct {
+	Signatures []struct {
+		Name       string `json:"name"`
+		PrivateKey string `json:"private_key"`
+		PublicKey  string `json:"public_key"`
+		Message    string `json:"message"`
+		Signature  string `json:"signature"`
+	} `json:"signatures"`
+
+	Accounts []struct {
+		PrivateKey string             `json:"private_key"`
+		PublicKey  string             `json:"public_key"`
+		Addresses  map[Network]string `json:"addresses"`
+	} `json:"accounts"`
+
+	Transactions []struct {
+		Name       string             `json:"name"`
+		NetworkID  string             `json:"network_id"`
+		PrivateKey string             `json:"private_key"`
+		Sender     string             `json:"sender"`
+		Request    TransactionRequest `json:"request"`
+		Encoding   string             `json:"encoding"`
+		Hash       string             `json:"hash"`
+		Signature  string             `json:"signature"`
+	} `json:"transactions"`
+
+	Mnemonics []struct {
+		Mnemonic   string `json:"mnemonic"`
+		Passphrase string `json:"passphrase"`
+		Seed       string `json:"seed"`
+		Path       string `json:"path"`
+		PrivateKey string `json:"private_key"`
+		PublicKey  string `json:"public_key"`
+		Address    string `json:"address"`
+	} `json:"mnemonics"`
+
+	HD []struct {
+		Mnemonic   string `json:"mnemonic"`
+		Path       string `json:"path"`
+		PrivateKey string `json:"private_key"`
+		PublicKey  string `json:"public_key"`
+		Address    string `json:"address"`
+	} `json:"hd"`
+}
+
+func loadTestVectors(t *testing.T) *testVectors {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/vectors.json")
+	require.NoError(t, err)
+
+	var vectors testVectors
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	return &vectors
+}
+
+func newTestWallet(t *testing.T) *WalletManager {
+	t.Helper()
+
+	client, err := NewClient(&ClientConfig{Network: NetworkMainnet})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client.Wallet
+}
+
+func TestVectorsSignatures(t *testing.T) {
+	for _, v := range loadTestVectors(t).Signatures {
+		t.Run(v.Name, func(t *testing.T) {
+			message, err := hex.DecodeString(v.Message)
+			require.NoError(t, err)
+
+			publicKey, err := DerivePublicKey(v.PrivateKey)
+			require.NoError(t, err)
+			assert.Equal(t, v.PublicKey, publicKey)
+
+			signature, err := SignMessage(v.PrivateKey, message)
+			require.NoError(t, err)
+			assert.Equal(t, v.Signature, signature)
+
+			ok, err := VerifySignature(v.PublicKey, message, v.Signature)
+			require.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestVectorsAddresses(t *testing.T) {
+	for _, v := range loadTestVectors(t).Accounts {
+		publicKey, err := DerivePublicKey(v.PrivateKey)
+		require.NoError(t, err)
+		assert.Equal(t, v.PublicKey, publicKey)
+
+		for network, want := range v.Addresses {
+			address, err := GenerateAddress(v.PublicKey, network)
+			require.NoError(t, err)
+			assert.Equal(t, want, address, network)
+
+			parsed, err := ParseAddress(want)
+			require.NoError(t, err)
+			assert.Equal(t, network, parsed.Network)
+			assert.Equal(t, AddressKindStandard, parsed.Kind)
+		}
+	}
+}
+
+func TestVectorsTransactions(t *testing.T) {
+	for _, v := range loadTestVectors(t).Transactions {
+		t.Run(v.Name, func(t *testing.T) {
+			encoding, err := EncodeTransaction(v.NetworkID, v.Sender, &v.Request)
+			require.NoError(t, err)
+			assert.Equal(t, v.Encoding, hex.EncodeToString(encoding))
+
+			hash, err := ComputeTransactionHash(v.NetworkID, v.Sender, &v.Request)
+			require.NoError(t, err)
+			assert.Equal(t, v.Hash, hash)
+
+			signer, err := NewLocalSigner(v.PrivateKey, Network(v.NetworkID))
+			require.NoError(t, err)
+			assert.Equal(t, v.Sender, signer.Address())
+
+			signed, err := newTransferTransaction(v.NetworkID, v.Sender, &v.Request).SignWith(context.Background(), signer)
+			require.NoError(t, err)
+			assert.Equal(t, v.Hash, signed.Hash)
+			assert.Equal(t, v.Signature, signed.Signature)
+			assert.NoError(t, signed.Verify())
+		})
+	}
+}
+
+func TestVectorsMnemonics(t *testing.T) {
+	wallet := newTestWallet(t)
+
+	for _, v := range loadTestVectors(t).Mnemonics {
+		seed, err := MnemonicToSeed(v.Mnemonic, v.Passphrase)
+		require.NoError(t, err)
+		assert.Equal(t, v.Seed, hex.EncodeToString(seed))
+		assert.Equal(t, v.Path, DefaultDerivationPath(0, 0).String())
+
+		account, err := wallet.CreateAccountFromMnemonic(v.Mnemonic, v.Passphrase)
+		require.NoError(t, err)
+		assert.Equal(t, v.PrivateKey, account.PrivateKey)
+		assert.Equal(t, v.PublicKey, account.PublicKey)
+		assert.Equal(t, v.Address, account.Address)
+	}
+}
+
+func TestVectorsHD(t *testing.T) {
+	wallet := newTestWallet(t)
+
+	for _, v := range loadTestVectors(t).HD {
+		t.Run(v.Path, func(t *testing.T) {
+			hd, err := wallet.NewHDWalletFromMnemonic(v.Mnemonic, "")
+			require.NoError(t, err)
+
+			account, err := hd.DeriveAccount(v.Path)
+			require.NoError(t, err)
+			assert.Equal(t, v.PrivateKey, account.PrivateKey)
+			assert.Equal(t, v.PublicKey, account.PublicKey)
+			assert.Equal(t, v.Address, account.Address)
+		})
+	}
+}
@@ -2,9 +2,11 @@ package chert
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
-	"encoding/hex"
+	"errors"
 	"fmt"
+	"time"
 )
 
 // WalletManager handles wallet operations and account management
@@ -24,7 +26,7 @@ func (wm *WalletManager) CreateAccount() (*Account, error) {
 		return nil, fmt.Errorf("failed to generate key pair: %w", err)
 	}
 
-	address, err := GenerateAddress(publicKey)
+	address, err := GenerateAddress(publicKey, wm.client.config.Network)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate address: %w", err)
 	}
@@ -43,7 +45,7 @@ func (wm *WalletManager) ImportAccount(privateKey string) (*Account, error) {
 		return nil, fmt.Errorf("failed to derive public key: %w", err)
 	}
 
-	address, err := GenerateAddress(publicKey)
+	address, err := GenerateAddress(publicKey, wm.client.config.Network)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate address: %w", err)
 	}
@@ -57,7 +59,7 @@ func (wm *WalletManager) ImportAccount(privateKey string) (*Account, error) {
 
 // CreateWatchOnlyAccount creates a watch-only account from a public key
 func (wm *WalletManager) CreateWatchOnlyAccount(publicKey string) (*Account, error) {
-	address, err := GenerateAddress(publicKey)
+	address, err := GenerateAddress(publicKey, wm.client.config.Network)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate address: %w", err)
 	}
@@ -71,46 +73,38 @@ func (wm *WalletManager) CreateWatchOnlyAccount(publicKey string) (*Account, err
 // GetBalance retrieves the balance for an account
 func (wm *WalletManager) GetBalance(ctx context.Context, address string) (*Balance, error) {
 	var result Balance
-	err := wm.client.rpcClient.Call(ctx, "getBalance", []interface{}{address}, &result)
+	err := wm.client.readCall(ctx, "getBalance", []interface{}{address}, &result)
 	return &result, err
 }
 
-// SendTransaction sends a transaction to the network
-func (wm *WalletManager) SendTransaction(ctx context.Context, request *TransactionRequest, account *Account) (string, error) {
-	if account.PrivateKey == "" {
-		return "", fmt.Errorf("account does not have a private key")
+// SendTransaction signs a transaction with signer and sends it to the network
+func (wm *WalletManager) SendTransaction(ctx context.Context, request *TransactionRequest, signer Signer) (string, error) {
+	if err := requireSigner(signer); err != nil {
+		return "", err
 	}
 
-	// Sign the transaction
-	signature, err := wm.signTransaction(request, account.PrivateKey)
+	unsigned, err := wm.BuildTransaction(signer.Address(), request)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign transaction: %w", err)
+		return "", err
 	}
 
-	tx := map[string]interface{}{
-		"sender":    account.Address,
-		"recipient": request.To,
-		"amount":    request.Amount,
-		"fee":       request.Fee,
-		"nonce":     request.Nonce,
-		"signature": signature,
-	}
+	return wm.client.signAndBroadcast(ctx, unsigned, signer)
+}
 
-	if request.Memo != "" {
-		tx["memo"] = request.Memo
+// BuildTransaction builds an unsigned transfer from sender on the client's
+// network, ready to be signed offline
+func (wm *WalletManager) BuildTransaction(sender string, request *TransactionRequest) (*UnsignedTransaction, error) {
+	if request == nil {
+		return nil, fmt.Errorf("transaction request is nil")
 	}
 
-	var result map[string]interface{}
-	err = wm.client.rpcClient.Call(ctx, "sendTransaction", []interface{}{tx}, &result)
-	if err != nil {
-		return "", err
-	}
-
-	if txHash, ok := result["hash"].(string); ok {
-		return txHash, nil
-	}
+	return checkUnsigned(newTransferTransaction(wm.client.networkID(), sender, request))
+}
 
-	return "", fmt.Errorf("invalid transaction response")
+// ComputeTransactionHash computes the hash a transaction will have once
+// broadcast from sender on the client's network
+func (wm *WalletManager) ComputeTransactionHash(sender string, request *TransactionRequest) (string, error) {
+	return ComputeTransactionHash(wm.client.networkID(), sender, request)
 }
 
 // EstimateFee estimates the fee for a transaction
@@ -120,79 +114,183 @@ func (wm *WalletManager) EstimateFee(ctx context.Context, request *TransactionRe
 	return &result, err
 }
 
-// WaitForTransaction waits for a transaction to be confirmed
-func (wm *WalletManager) WaitForTransaction(ctx context.Context, txHash string, timeoutMs uint64) (*Transaction, error) {
-	// Default timeout of 60 seconds
-	if timeoutMs == 0 {
-		timeoutMs = 60000
+// WaitOptions configures WaitForTransaction. Zero fields take the defaults
+// from DefaultWaitOptions.
+type WaitOptions struct {
+	// PollInterval is the delay before the second poll
+	PollInterval time.Duration
+
+	// MaxPollInterval caps the delay between polls
+	MaxPollInterval time.Duration
+
+	// BackoffFactor multiplies the delay after every poll
+	BackoffFactor float64
+
+	// Timeout bounds the whole wait, in addition to any context deadline
+	Timeout time.Duration
+
+	// Confirmations is the number of blocks, counting the one that includes
+	// the transaction, that must exist before it counts as confirmed
+	Confirmations uint64
+
+	// MaxConsecutiveErrors is how many transport or RPC errors in a row are
+	// tolerated before giving up. Not-found responses never count.
+	MaxConsecutiveErrors int
+}
+
+// DefaultWaitOptions returns the options used for zero WaitOptions fields
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		PollInterval:         2 * time.Second,
+		MaxPollInterval:      15 * time.Second,
+		BackoffFactor:        1.5,
+		Timeout:              60 * time.Second,
+		Confirmations:        1,
+		MaxConsecutiveErrors: 3,
 	}
+}
 
-	interval := uint64(2000) // 2 seconds
-	startTime := uint64(0)   // Would use time.Now() in real implementation
+// withDefaults fills zero fields from DefaultWaitOptions
+func (o *WaitOptions) withDefaults() WaitOptions {
+	defaults := DefaultWaitOptions()
+	if o == nil {
+		return defaults
+	}
 
-	for startTime < timeoutMs {
-		tx, err := wm.client.GetTransaction(ctx, txHash)
-		if err != nil {
-			// Continue polling if transaction not found yet
-			continue
-		}
+	opts := *o
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.MaxPollInterval <= 0 {
+		opts.MaxPollInterval = defaults.MaxPollInterval
+	}
+	if opts.MaxPollInterval < opts.PollInterval {
+		opts.MaxPollInterval = opts.PollInterval
+	}
+	if opts.BackoffFactor < 1 {
+		opts.BackoffFactor = defaults.BackoffFactor
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	if opts.Confirmations == 0 {
+		opts.Confirmations = defaults.Confirmations
+	}
+	if opts.MaxConsecutiveErrors <= 0 {
+		opts.MaxConsecutiveErrors = defaults.MaxConsecutiveErrors
+	}
+	return opts
+}
 
-		if tx.Status == string(TxStatusConfirmed) {
+// WaitForTransaction polls until a transaction is confirmed with the
+// required number of confirmations, backing off between polls.
+//
+// It returns ErrTransactionFailed (wrapped) with the transaction if it
+// failed or was rejected, ErrWaitTimeout (wrapped) with the last seen
+// transaction, if any, when opts.Timeout elapses, and ctx.Err() if ctx is
+// done first. Unknown hashes are polled until the timeout, since a freshly
+// broadcast transaction may not have propagated yet.
+func (wm *WalletManager) WaitForTransaction(ctx context.Context, txHash string, opts *WaitOptions) (*Transaction, error) {
+	options := opts.withDefaults()
+
+	waitCtx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	var (
+		lastSeen *Transaction
+		lastErr  error
+		errCount int
+	)
+
+	interval := options.PollInterval
+	for {
+		tx, err := wm.pollTransaction(waitCtx, txHash, options.Confirmations)
+		switch {
+		case err == nil:
 			return tx, nil
+		case errors.Is(err, ErrTransactionFailed):
+			return tx, err
+		case errors.Is(err, errNotConfirmed):
+			lastSeen, lastErr, errCount = tx, nil, 0
+		case errors.Is(err, ErrTransactionNotFound):
+			lastErr, errCount = nil, 0
+		case waitCtx.Err() != nil:
+			// The error was caused by the deadline; report it below
+		default:
+			lastErr = err
+			errCount++
+			if errCount >= options.MaxConsecutiveErrors {
+				return lastSeen, fmt.Errorf("failed to poll transaction %s: %w", txHash, err)
+			}
 		}
 
-		if tx.Status == string(TxStatusFailed) || tx.Status == string(TxStatusRejected) {
-			return nil, fmt.Errorf("transaction %s", tx.Status)
+		timer := time.NewTimer(interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			if ctx.Err() != nil {
+				return lastSeen, ctx.Err()
+			}
+			if lastErr != nil {
+				return lastSeen, fmt.Errorf("%w: %s after %s: last error: %v", ErrWaitTimeout, txHash, options.Timeout, lastErr)
+			}
+			return lastSeen, fmt.Errorf("%w: %s after %s", ErrWaitTimeout, txHash, options.Timeout)
+		case <-timer.C:
 		}
 
-		// Wait before next poll
-		// In real implementation, would use time.Sleep
-		startTime += interval
+		interval = time.Duration(float64(interval) * options.BackoffFactor)
+		if interval > options.MaxPollInterval {
+			interval = options.MaxPollInterval
+		}
 	}
-
-	return nil, fmt.Errorf("transaction confirmation timeout")
 }
 
-// generateKeyPair generates a new Ed25519 keypair
-func (wm *WalletManager) generateKeyPair() (string, string, error) {
-	// Generate 32 bytes of random data for private key
-	privateKeyBytes := make([]byte, 32)
-	if _, err := rand.Read(privateKeyBytes); err != nil {
-		return "", "", fmt.Errorf("failed to generate random bytes: %w", err)
-	}
+// errNotConfirmed marks a transaction that is known but not yet confirmed
+var errNotConfirmed = errors.New("transaction not confirmed")
 
-	// In a real implementation, this would derive the public key from the private key
-	// For now, we'll just use the private key bytes as public key for simplicity
-	publicKeyBytes := make([]byte, 32)
-	copy(publicKeyBytes, privateKeyBytes)
+// pollTransaction fetches a transaction once and checks its status and
+// confirmation depth
+func (wm *WalletManager) pollTransaction(ctx context.Context, txHash string, confirmations uint64) (*Transaction, error) {
+	tx, err := wm.client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
 
-	privateKey := hex.EncodeToString(privateKeyBytes)
-	publicKey := hex.EncodeToString(publicKeyBytes)
+	switch TransactionStatus(tx.Status) {
+	case TxStatusFailed, TxStatusRejected:
+		return tx, fmt.Errorf("%w: %s %s", ErrTransactionFailed, txHash, tx.Status)
+	case TxStatusConfirmed:
+	default:
+		return tx, errNotConfirmed
+	}
 
-	return privateKey, publicKey, nil
-}
+	if confirmations <= 1 {
+		return tx, nil
+	}
 
-// derivePublicKey derives the public key from a private key
-func (wm *WalletManager) derivePublicKey(privateKey string) (string, error) {
-	privateKeyBytes, err := hex.DecodeString(privateKey)
+	latest, err := wm.client.GetLatestBlock(ctx)
 	if err != nil {
-		return "", fmt.Errorf("invalid private key hex: %w", err)
+		return tx, err
+	}
+
+	if latest.Height < tx.BlockHeight || latest.Height-tx.BlockHeight+1 < confirmations {
+		return tx, errNotConfirmed
 	}
 
-	if len(privateKeyBytes) != 32 {
-		return "", fmt.Errorf("invalid private key length")
+	return tx, nil
+}
+
+// generateKeyPair generates a new Ed25519 keypair
+func (wm *WalletManager) generateKeyPair() (string, string, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return "", "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
-	// In a real implementation, this would properly derive the public key
-	// For now, we'll just return the private key bytes as public key
-	publicKey := hex.EncodeToString(privateKeyBytes)
-	return publicKey, nil
+	return KeyPairFromSeed(seed)
 }
 
-// signTransaction signs a transaction with the private key
-func (wm *WalletManager) signTransaction(request *TransactionRequest, privateKey string) (string, error) {
-	// In a real implementation, this would create a proper transaction hash
-	// and sign it with the private key using Ed25519
-	// For now, we'll return a mock signature
-	return "mock_signature_" + GenerateTxID(), nil
-}
\ No newline at end of file
+// derivePublicKey derives the public key from a private key
+func (wm *WalletManager) derivePublicKey(privateKey string) (string, error) {
+	return DerivePublicKey(privateKey)
+}
@@ -0,0 +1,192 @@
+package chert
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTxHash = "5b0c2d9b1f0a6c3e8d7f4a2b9c1e0d3f6a8b7c5d4e3f2a1b0c9d8e7f6a5b4c3d"
+
+// testWaitOptions polls quickly and gives up after a second
+func testWaitOptions() *WaitOptions {
+	return &WaitOptions{
+		PollInterval:    time.Millisecond,
+		MaxPollInterval: 5 * time.Millisecond,
+		Timeout:         time.Second,
+	}
+}
+
+// replyTransaction answers a getTransaction call with the test transaction
+// in status, included at height
+func replyTransaction(w http.ResponseWriter, req *testRPCRequest, status TransactionStatus, height uint64) {
+	req.reply(w, map[string]interface{}{
+		"hash":         testTxHash,
+		"from":         testMainnetAddress,
+		"to":           testValidatorAddress,
+		"amount":       "1",
+		"fee":          "0.01",
+		"status":       status,
+		"block_height": height,
+	})
+}
+
+// dropConnection closes the connection without answering, so the client
+// sees a transport error
+func dropConnection(t *testing.T, w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	require.NoError(t, err)
+	conn.Close()
+}
+
+// newTestWaitClient creates a client that sends every getTransaction call
+// to poll, numbered from 1, and does not retry
+func newTestWaitClient(t *testing.T, poll func(w http.ResponseWriter, req *testRPCRequest, n int32)) (*ChertClient, *int32) {
+	t.Helper()
+
+	var polls int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		if req.Method != "getTransaction" {
+			req.fail(w, -32601, "method not found")
+			return
+		}
+		poll(w, req, atomic.AddInt32(&polls, 1))
+	})
+	return newTestClient(t, server, &ClientConfig{Retry: &RetryPolicy{MaxAttempts: 1}}), &polls
+}
+
+func TestWaitForTransactionUntilIncluded(t *testing.T) {
+	client, polls := newTestWaitClient(t, func(w http.ResponseWriter, req *testRPCRequest, n int32) {
+		switch n {
+		case 1:
+			req.reply(w, nil)
+		case 2:
+			req.fail(w, -32004, "transaction not found")
+		case 3:
+			replyTransaction(w, req, TxStatusPending, 0)
+		default:
+			replyTransaction(w, req, TxStatusConfirmed, 10)
+		}
+	})
+
+	tx, err := client.Wallet.WaitForTransaction(context.Background(), testTxHash, testWaitOptions())
+	require.NoError(t, err)
+	assert.Equal(t, string(TxStatusConfirmed), tx.Status)
+	assert.EqualValues(t, 4, atomic.LoadInt32(polls))
+}
+
+func TestWaitForTransactionPollErrors(t *testing.T) {
+	t.Run("retried", func(t *testing.T) {
+		client, polls := newTestWaitClient(t, func(w http.ResponseWriter, req *testRPCRequest, n int32) {
+			switch n {
+			case 1:
+				dropConnection(t, w)
+			case 2:
+				http.Error(w, "upstream failed", http.StatusBadGateway)
+			default:
+				replyTransaction(w, req, TxStatusConfirmed, 10)
+			}
+		})
+
+		_, err := client.Wallet.WaitForTransaction(context.Background(), testTxHash, testWaitOptions())
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, atomic.LoadInt32(polls))
+	})
+
+	t.Run("returned", func(t *testing.T) {
+		client, polls := newTestWaitClient(t, func(w http.ResponseWriter, req *testRPCRequest, n int32) {
+			if n == 1 {
+				replyTransaction(w, req, TxStatusPending, 0)
+				return
+			}
+			dropConnection(t, w)
+		})
+
+		options := testWaitOptions()
+		options.MaxConsecutiveErrors = 2
+		tx, err := client.Wallet.WaitForTransaction(context.Background(), testTxHash, options)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrWaitTimeout)
+		assert.True(t, isTransportError(err), err)
+		assert.EqualValues(t, 3, atomic.LoadInt32(polls))
+
+		// The last state seen is returned with the error
+		require.NotNil(t, tx)
+		assert.Equal(t, string(TxStatusPending), tx.Status)
+	})
+}
+
+func TestWaitForTransactionFailed(t *testing.T) {
+	for _, status := range []TransactionStatus{TxStatusFailed, TxStatusRejected} {
+		client, _ := newTestWaitClient(t, func(w http.ResponseWriter, req *testRPCRequest, n int32) {
+			replyTransaction(w, req, status, 10)
+		})
+
+		tx, err := client.Wallet.WaitForTransaction(context.Background(), testTxHash, testWaitOptions())
+		assert.ErrorIs(t, err, ErrTransactionFailed, status)
+		require.NotNil(t, tx)
+		assert.Equal(t, string(status), tx.Status)
+	}
+}
+
+func TestWaitForTransactionStops(t *testing.T) {
+	client, _ := newTestWaitClient(t, func(w http.ResponseWriter, req *testRPCRequest, n int32) {
+		replyTransaction(w, req, TxStatusPending, 0)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		options := testWaitOptions()
+		options.Timeout = 30 * time.Millisecond
+
+		tx, err := client.Wallet.WaitForTransaction(context.Background(), testTxHash, options)
+		assert.ErrorIs(t, err, ErrWaitTimeout)
+		require.NotNil(t, tx)
+		assert.Equal(t, string(TxStatusPending), tx.Status)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(30*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := client.Wallet.WaitForTransaction(ctx, testTxHash, testWaitOptions())
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrWaitTimeout)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Wallet.WaitForTransaction(ctx, testTxHash, testWaitOptions())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWaitForTransactionConfirmations(t *testing.T) {
+	var latestCalls int32
+	server := newTestRPCServer(t, func(w http.ResponseWriter, req *testRPCRequest) {
+		switch req.Method {
+		case "getTransaction":
+			replyTransaction(w, req, TxStatusConfirmed, 10)
+		case "getLatestBlock":
+			// The chain grows by one block per call: 10, 11, 12, ...
+			height := 9 + atomic.AddInt32(&latestCalls, 1)
+			req.reply(w, map[string]interface{}{"height": height})
+		}
+	})
+	client := newTestClient(t, server, nil)
+
+	options := testWaitOptions()
+	options.Confirmations = 3
+	tx, err := client.Wallet.WaitForTransaction(context.Background(), testTxHash, options)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, tx.BlockHeight)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&latestCalls))
+}
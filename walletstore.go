@@ -0,0 +1,293 @@
+package chert
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrWalletLocked is returned when a private key is needed while the
+	// wallet store is locked
+	ErrWalletLocked = errors.New("wallet is locked")
+
+	// ErrWatchOnlyAccount is returned when a private key is requested for a
+	// watch-only account
+	ErrWatchOnlyAccount = errors.New("account is watch-only")
+
+	// ErrAccountExists is returned when adding an account whose address or
+	// label is already in the wallet store
+	ErrAccountExists = errors.New("account already exists")
+)
+
+// WalletStore keeps accounts in a Keyring. Private keys are encrypted with
+// the wallet passphrase; Unlock holds the passphrase and the decrypted keys
+// in memory until Lock is called.
+type WalletStore struct {
+	wallet  *WalletManager
+	keyring Keyring
+
+	// KDFParams are the scrypt parameters used for newly added accounts
+	KDFParams ScryptParams
+
+	mu         sync.Mutex
+	passphrase string
+	unlocked   map[string]*Account
+}
+
+// NewWalletStore creates a wallet store backed by keyring. The store starts
+// locked.
+func (wm *WalletManager) NewWalletStore(keyring Keyring) *WalletStore {
+	return &WalletStore{
+		wallet:    wm,
+		keyring:   keyring,
+		KDFParams: StandardScryptParams,
+	}
+}
+
+// OpenWalletStore creates a wallet store that persists accounts to dir
+func (wm *WalletManager) OpenWalletStore(dir string) (*WalletStore, error) {
+	keyring, err := NewDirectoryKeyring(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return wm.NewWalletStore(keyring), nil
+}
+
+// Unlock checks the passphrase against every stored private key and keeps
+// it in memory for adding and signing with accounts. It fails if any key
+// does not decrypt, so that a store never ends up with keys under different
+// passphrases. The first Unlock of a store without private keys sets its
+// passphrase.
+func (s *WalletStore) Unlock(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("wallet passphrase is required")
+	}
+
+	entries, err := s.keyring.List()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlocked := make(map[string]*Account)
+	for _, entry := range entries {
+		if entry.WatchOnly || entry.Keystore == nil {
+			continue
+		}
+
+		account, err := entry.Keystore.Decrypt(passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", entry.Address, err)
+		}
+		unlocked[entry.Address] = account
+	}
+
+	s.passphrase = passphrase
+	s.unlocked = unlocked
+	return nil
+}
+
+// Lock forgets the passphrase and every decrypted key
+func (s *WalletStore) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.passphrase = ""
+	s.unlocked = nil
+}
+
+// IsLocked reports whether the wallet store is locked
+func (s *WalletStore) IsLocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.passphrase == ""
+}
+
+// Add stores an account under an optional label. Accounts without a private
+// key, such as those from CreateWatchOnlyAccount, are stored watch-only and
+// can be added while locked; others are encrypted with the passphrase and
+// require the store to be unlocked.
+func (s *WalletStore) Add(account *Account, label string) (*KeyringEntry, error) {
+	if account == nil || account.Address == "" {
+		return nil, fmt.Errorf("account is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkUnique(account.Address, label); err != nil {
+		return nil, err
+	}
+
+	entry := &KeyringEntry{
+		Address:   account.Address,
+		PublicKey: account.PublicKey,
+		Label:     label,
+		CreatedAt: time.Now().UTC(),
+		WatchOnly: account.PrivateKey == "",
+	}
+
+	if !entry.WatchOnly {
+		if s.passphrase == "" {
+			return nil, ErrWalletLocked
+		}
+
+		ks, err := EncryptKeystore(account, s.passphrase, s.KDFParams)
+		if err != nil {
+			return nil, err
+		}
+		entry.PublicKey = ks.PublicKey
+		entry.Keystore = ks
+	}
+
+	if err := s.keyring.Put(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// List returns every stored account, oldest first
+func (s *WalletStore) List() ([]*KeyringEntry, error) {
+	entries, err := s.keyring.List()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	return entries, nil
+}
+
+// Get returns the stored account with address
+func (s *WalletStore) Get(address string) (*KeyringEntry, error) {
+	return s.keyring.Get(address)
+}
+
+// GetByLabel returns the stored account with label
+func (s *WalletStore) GetByLabel(label string) (*KeyringEntry, error) {
+	entries, err := s.keyring.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if label != "" && entry.Label == label {
+			return entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: label %q", ErrAccountNotFound, label)
+}
+
+// SetLabel changes the label of a stored account
+func (s *WalletStore) SetLabel(address, label string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.keyring.Get(address)
+	if err != nil {
+		return err
+	}
+
+	if label != "" && label != entry.Label {
+		if err := s.checkUnique("", label); err != nil {
+			return err
+		}
+	}
+
+	entry.Label = label
+	return s.keyring.Put(entry)
+}
+
+// Remove deletes a stored account
+func (s *WalletStore) Remove(address string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.keyring.Delete(address); err != nil {
+		return err
+	}
+
+	delete(s.unlocked, address)
+	return nil
+}
+
+// Account returns the stored account with its private key. It returns
+// ErrWalletLocked while locked and ErrWatchOnlyAccount for watch-only
+// accounts.
+func (s *WalletStore) Account(address string) (*Account, error) {
+	entry, err := s.keyring.Get(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.WatchOnly || entry.Keystore == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWatchOnlyAccount, address)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.passphrase == "" {
+		return nil, ErrWalletLocked
+	}
+
+	if account, ok := s.unlocked[address]; ok {
+		copied := *account
+		return &copied, nil
+	}
+
+	account, err := entry.Keystore.Decrypt(s.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", address, err)
+	}
+
+	s.unlocked[address] = account
+	copied := *account
+	return &copied, nil
+}
+
+// Signer returns a signer for a stored account
+func (s *WalletStore) Signer(address string) (Signer, error) {
+	account, err := s.Account(address)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := account.Signer()
+	if err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// checkUnique fails if address or a non-empty label is already stored.
+// Callers must hold s.mu.
+func (s *WalletStore) checkUnique(address, label string) error {
+	entries, err := s.keyring.List()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if address != "" && entry.Address == address {
+			return fmt.Errorf("%w: %s", ErrAccountExists, address)
+		}
+		if label != "" && entry.Label == label {
+			return fmt.Errorf("%w: label %q", ErrAccountExists, label)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package chert
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestWalletStore opens a wallet store in dir with fast key derivation
+func openTestWalletStore(t *testing.T, wallet *WalletManager, dir string) *WalletStore {
+	t.Helper()
+
+	store, err := wallet.OpenWalletStore(dir)
+	require.NoError(t, err)
+	store.KDFParams = testScryptParams
+	return store
+}
+
+func TestWalletStoreRoundTrip(t *testing.T) {
+	wallet := newTestWallet(t)
+	dir := filepath.Join(t.TempDir(), "wallet")
+	store := openTestWalletStore(t, wallet, dir)
+
+	first, err := wallet.CreateAccount()
+	require.NoError(t, err)
+	second, err := wallet.CreateAccount()
+	require.NoError(t, err)
+	third, err := wallet.CreateAccount()
+	require.NoError(t, err)
+	watchOnly, err := wallet.CreateWatchOnlyAccount(third.PublicKey)
+	require.NoError(t, err)
+
+	// Watch-only accounts need no passphrase; others do
+	_, err = store.Add(watchOnly, "cold storage")
+	require.NoError(t, err)
+	_, err = store.Add(first, "savings")
+	assert.ErrorIs(t, err, ErrWalletLocked)
+
+	require.NoError(t, store.Unlock("correct horse"))
+	_, err = store.Add(first, "savings")
+	require.NoError(t, err)
+	_, err = store.Add(second, "spending")
+	require.NoError(t, err)
+
+	_, err = store.Add(first, "other")
+	assert.ErrorIs(t, err, ErrAccountExists)
+	_, err = store.Add(watchOnly, "savings")
+	assert.ErrorIs(t, err, ErrAccountExists)
+
+	// A fresh store on the same directory sees everything, oldest first
+	store.Lock()
+	store = openTestWalletStore(t, wallet, dir)
+	assert.True(t, store.IsLocked())
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"cold storage", "savings", "spending"}, []string{entries[0].Label, entries[1].Label, entries[2].Label})
+	assert.True(t, entries[0].WatchOnly)
+	assert.Nil(t, entries[0].Keystore)
+
+	_, err = store.Account(first.Address)
+	assert.ErrorIs(t, err, ErrWalletLocked)
+
+	assert.ErrorIs(t, store.Unlock("wrong horse"), ErrInvalidPassword)
+	assert.True(t, store.IsLocked())
+	require.NoError(t, store.Unlock("correct horse"))
+
+	entry, err := store.GetByLabel("spending")
+	require.NoError(t, err)
+	account, err := store.Account(entry.Address)
+	require.NoError(t, err)
+	assert.Equal(t, second, account)
+
+	signer, err := store.Signer(first.Address)
+	require.NoError(t, err)
+	assert.Equal(t, first.Address, signer.Address())
+
+	_, err = store.Account(watchOnly.Address)
+	assert.ErrorIs(t, err, ErrWatchOnlyAccount)
+
+	require.NoError(t, store.SetLabel(second.Address, "daily"))
+	_, err = store.GetByLabel("spending")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.ErrorIs(t, store.SetLabel(first.Address, "daily"), ErrAccountExists)
+
+	require.NoError(t, store.Remove(first.Address))
+	_, err = store.Get(first.Address)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	_, err = store.Account(first.Address)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.ErrorIs(t, store.Remove(first.Address), ErrAccountNotFound)
+
+	store.Lock()
+	assert.True(t, store.IsLocked())
+	_, err = store.Account(second.Address)
+	assert.ErrorIs(t, err, ErrWalletLocked)
+}
+
+func TestWalletStoreUnlockChecksEveryKey(t *testing.T) {
+	wallet := newTestWallet(t)
+	keyring := NewMemoryKeyring()
+	store := wallet.NewWalletStore(keyring)
+	store.KDFParams = testScryptParams
+
+	require.NoError(t, store.Unlock("correct horse"))
+	for i := 0; i < 2; i++ {
+		account, err := wallet.CreateAccount()
+		require.NoError(t, err)
+		_, err = store.Add(account, "")
+		require.NoError(t, err)
+	}
+	store.Lock()
+
+	// An entry encrypted under another passphrase, e.g. by another tool
+	other, ks := newTestKeystore(t, wallet, "battery staple")
+	require.NoError(t, keyring.Put(&KeyringEntry{Address: other.Address, PublicKey: ks.PublicKey, Keystore: ks}))
+
+	for _, passphrase := range []string{"correct horse", "battery staple"} {
+		err := store.Unlock(passphrase)
+		assert.ErrorIs(t, err, ErrInvalidPassword, passphrase)
+		assert.True(t, store.IsLocked(), passphrase)
+	}
+
+	require.NoError(t, keyring.Delete(other.Address))
+	require.NoError(t, store.Unlock("correct horse"))
+}
+
+func TestWalletStoreFirstUnlock(t *testing.T) {
+	wallet := newTestWallet(t)
+	store := openTestWalletStore(t, wallet, t.TempDir())
+
+	assert.Error(t, store.Unlock(""))
+
+	// Any passphrase unlocks a store without private keys, and becomes its
+	// passphrase once a key is added
+	require.NoError(t, store.Unlock("first"))
+	account, err := wallet.CreateAccount()
+	require.NoError(t, err)
+	_, err = store.Add(account, "")
+	require.NoError(t, err)
+	store.Lock()
+
+	assert.ErrorIs(t, store.Unlock("second"), ErrInvalidPassword)
+	require.NoError(t, store.Unlock("first"))
+}